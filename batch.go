@@ -0,0 +1,173 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BatchElem is a single request in a batch call.
+//
+// Result must be a pointer to the value the result should be unmarshalled into, or nil to discard it.
+// After the batch call returns, Error is set if this particular request failed.
+type BatchElem struct {
+	Method string
+	Params []interface{}
+	Result interface{}
+	Error  error
+}
+
+// BatchCall sends all given requests as a single JSON-RPC batch request.
+//
+// The returned error only covers failures of the batch as a whole (e.g. network errors). Errors of individual
+// requests are stored in the Error field of the corresponding BatchElem.
+// An empty batch isn't sent.
+func (rpc *FlashbotsRPC) BatchCall(batch []BatchElem) error {
+	return rpc.BatchCallContext(context.Background(), batch)
+}
+
+// BatchCallContext is like BatchCall but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) BatchCallContext(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(newBatchRequest(batch))
+	if err != nil {
+		return err
+	}
+
+	methods := batchMethods(batch)
+	data, statusCode, err := rpc.post(ctx, body, "", nil, methods...)
+	if err != nil {
+		return err
+	}
+
+	if rpc.Debug {
+		rpc.log.Println(fmt.Sprintf("batch (%d requests)\nRequest: %s\nResponse: %s\n", len(batch), body, data))
+	}
+
+	return processBatchResponse(batch, httpResponse{method: strings.Join(methods, ","), endpoint: rpc.url, statusCode: statusCode, body: data}, false)
+}
+
+// BatchCallWithFlashbotsSignature is like BatchCall, but signs the whole batch request like CallWithFlashbotsSignature
+func (rpc *FlashbotsRPC) BatchCallWithFlashbotsSignature(privKey *ecdsa.PrivateKey, batch []BatchElem) error {
+	return rpc.BatchCallWithFlashbotsSignatureContext(context.Background(), NewPrivateKeySigner(privKey), batch)
+}
+
+// BatchCallWithFlashbotsSignatureContext is like BatchCallWithFlashbotsSignature but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) BatchCallWithFlashbotsSignatureContext(ctx context.Context, signer Signer, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(newBatchRequest(batch))
+	if err != nil {
+		return err
+	}
+
+	signature, err := flashbotsSignature(ctx, body, signer)
+	if err != nil {
+		return err
+	}
+
+	methods := batchMethods(batch)
+	data, statusCode, err := rpc.post(ctx, body, signature, signer, methods...)
+	if err != nil {
+		return err
+	}
+
+	if rpc.Debug {
+		rpc.log.Println(fmt.Sprintf("batch (%d requests)\nRequest: %s\nSignature: %s\nResponse: %s\n", len(batch), body, signature, data))
+	}
+
+	return processBatchResponse(batch, httpResponse{method: strings.Join(methods, ","), endpoint: rpc.url, statusCode: statusCode, body: data}, true)
+}
+
+// newBatchRequest creates the requests for a batch. The request ID is the index in the batch plus one.
+func newBatchRequest(batch []BatchElem) []rpcRequest {
+	requests := make([]rpcRequest, len(batch))
+	for i, elem := range batch {
+		requests[i] = rpcRequest{
+			ID:      i + 1,
+			JSONRPC: "2.0",
+			Method:  elem.Method,
+			Params:  elem.Params,
+		}
+	}
+	return requests
+}
+
+// batchMethods returns the methods of all requests in the batch
+func batchMethods(batch []BatchElem) []string {
+	methods := make([]string, len(batch))
+	for i, elem := range batch {
+		methods[i] = elem.Method
+	}
+	return methods
+}
+
+// processBatchResponse matches the responses back to the batch elements by ID, as the server may answer in any order.
+// With relay set, relay errors are recognized and errors are returned as RelayErrors, see httpResponse.result.
+func processBatchResponse(batch []BatchElem, res httpResponse, relay bool) error {
+	// On error, the relay doesn't answer with a batch but with a single error object: {"error":"..."}
+	if relay {
+		if err := res.errorResponse(); err != nil {
+			return err
+		}
+	}
+
+	// A non-2xx status code fails the batch as a whole, even if the body is a batch response
+	if !res.isSuccess() {
+		single := new(rpcResponse)
+		if err := json.Unmarshal(res.body, single); err == nil && single.Error != nil {
+			_, err := res.result(relay)
+			return err
+		}
+		return res.invalid(relay, "unexpected HTTP status code")
+	}
+
+	responses := []rpcResponse{}
+	if err := json.Unmarshal(res.body, &responses); err != nil {
+		// Some servers answer an invalid batch with a single error response
+		if _, err := res.result(relay); err != nil {
+			return err
+		}
+		return res.invalid(relay, "invalid JSON-RPC batch response")
+	}
+
+	received := make([]bool, len(batch))
+	for _, resp := range responses {
+		index := resp.ID - 1
+		if index < 0 || index >= len(batch) || received[index] {
+			return fmt.Errorf("%w: %d", ErrInvalidBatchResponseID, resp.ID)
+		}
+		received[index] = true
+		if resp.JSONRPC != "2.0" {
+			return res.invalid(relay, "invalid JSON-RPC response version")
+		}
+
+		elem := &batch[index]
+		elem.Error = nil
+		switch {
+		case resp.Error != nil && relay:
+			elemResp := res
+			elemResp.method = elem.Method
+			elem.Error = elemResp.relayError(resp.Error.Code, resp.Error.Message, resp.Error.Data)
+		case resp.Error != nil:
+			elem.Error = *resp.Error
+		case elem.Result != nil:
+			elem.Error = json.Unmarshal(resp.Result, elem.Result)
+		}
+	}
+
+	for i := range batch {
+		if !received[i] {
+			batch[i].Error = ErrMissingBatchResponse
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		requests := []rpcRequest{}
+		require.NoError(t, json.Unmarshal(body, &requests))
+		require.Len(t, requests, 4)
+		for i, request := range requests {
+			require.Equal(t, i+1, request.ID)
+		}
+		require.Equal(t, "eth_getBalance", requests[1].Method)
+		require.Equal(t, []interface{}{"0x407d73d8a49eeb85d32cf465507dd71d507100c1", "latest"}, requests[1].Params)
+
+		// out of order, with an error for the third request and no response for the fourth
+		_, err = w.Write([]byte(`[
+			{"jsonrpc":"2.0","id":3,"error":{"code":-32000,"message":"header not found"}},
+			{"jsonrpc":"2.0","id":2,"result":"0x1"},
+			{"jsonrpc":"2.0","id":1,"result":"0xbc614e"}
+		]`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	var blockNumber, balance, nonce string
+	batch := []BatchElem{
+		{Method: "eth_blockNumber", Result: &blockNumber},
+		{Method: "eth_getBalance", Params: []interface{}{"0x407d73d8a49eeb85d32cf465507dd71d507100c1", "latest"}, Result: &balance},
+		{Method: "eth_getTransactionCount", Params: []interface{}{"0x407d73d8a49eeb85d32cf465507dd71d507100c1", "0x1"}, Result: &nonce},
+		{Method: "eth_chainId"},
+	}
+	err := rpc.BatchCall(batch)
+	require.NoError(t, err)
+
+	require.NoError(t, batch[0].Error)
+	require.Equal(t, "0xbc614e", blockNumber)
+	require.NoError(t, batch[1].Error)
+	require.Equal(t, "0x1", balance)
+
+	rpcErr, ok := batch[2].Error.(RpcError)
+	require.True(t, ok)
+	require.Equal(t, -32000, rpcErr.Code)
+	require.Equal(t, "", nonce)
+
+	require.True(t, errors.Is(batch[3].Error, ErrMissingBatchResponse))
+}
+
+func TestBatchCallInvalidID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`[{"jsonrpc":"2.0","id":5,"result":"0x1"}]`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	err := rpc.BatchCall([]BatchElem{{Method: "eth_blockNumber"}})
+	require.True(t, errors.Is(err, ErrInvalidBatchResponseID))
+}
+
+func TestBatchCallWithFlashbotsSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		expectedSignature, err := flashbotsSignature(context.Background(), body, NewPrivateKeySigner(key))
+		require.NoError(t, err)
+		require.Equal(t, expectedSignature, r.Header.Get("X-Flashbots-Signature"))
+
+		_, err = w.Write([]byte(`[
+			{"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"unable to decode txs"}},
+			{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x1234"}}
+		]`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	res := FlashbotsSendBundleResponse{}
+	batch := []BatchElem{
+		{Method: "eth_sendBundle", Params: []interface{}{FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"}}, Result: &res},
+		{Method: "eth_sendBundle", Params: []interface{}{FlashbotsSendBundleRequest{Txs: []string{"0x02"}, BlockNumber: "0x1"}}},
+	}
+	err = rpc.BatchCallWithFlashbotsSignature(key, batch)
+	require.NoError(t, err)
+	require.NoError(t, batch[0].Error)
+	require.Equal(t, "0x1234", res.BundleHash)
+	require.True(t, errors.Is(batch[1].Error, ErrRelayErrorResponse))
+}
+
+func TestBatchCallWithFlashbotsSignatureRelayError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err := w.Write([]byte(`{"error":"missing signature"}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	err = rpc.BatchCallWithFlashbotsSignature(key, []BatchElem{{Method: "eth_sendBundle"}})
+	require.True(t, errors.Is(err, ErrRelayErrorResponse))
+}
+
+func TestBatchCallStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, err := w.Write([]byte(`[{"jsonrpc":"2.0","id":1,"result":"0x1"}]`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	var blockNumber string
+	err := rpc.BatchCall([]BatchElem{{Method: "eth_blockNumber", Result: &blockNumber}})
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	require.Equal(t, "", blockNumber)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	err = rpc.BatchCallWithFlashbotsSignature(key, []BatchElem{{Method: "eth_sendBundle"}})
+	var relayErr *RelayError
+	require.True(t, errors.As(err, &relayErr))
+	require.Equal(t, http.StatusBadGateway, relayErr.StatusCode)
+}
+
+func TestBatchCallEmpty(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, err := w.Write([]byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"empty batch"}}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	require.NoError(t, rpc.BatchCall(nil))
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	require.NoError(t, rpc.BatchCallWithFlashbotsSignature(key, []BatchElem{}))
+	require.Equal(t, 0, requests)
+}
@@ -0,0 +1,496 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrBroadcastPending is the error of builders which hadn't answered yet when a broadcast returned early
+var ErrBroadcastPending = errors.New("builder did not answer before the broadcast returned")
+
+// ErrAllBuildersFailed means a broadcast wasn't accepted by any builder
+var ErrAllBuildersFailed = errors.New("not accepted by any builder")
+
+// BroadcastPolicy decides when a broadcast returns. The zero value waits for all builders.
+//
+// When MinSuccesses and Deadline are both set, the broadcast returns at whichever comes first.
+type BroadcastPolicy struct {
+	MinSuccesses    int           // Return as soon as this many builders answered successfully, 0 waits for all builders
+	Deadline        time.Duration // Return after this duration with partial results, 0 means no deadline besides Timeout
+	CancelRemaining bool          // Cancel requests still outstanding when returning early, otherwise they complete in the background
+}
+
+// BuilderEndpoint configures a single builder of a BuilderBroadcastRPC
+type BuilderEndpoint struct {
+	URL              string
+	Name             string            // (Optional) Name of the builder in results and logs, defaults to the URL
+	Headers          map[string]string // (Optional) Additional headers for this builder, added after the broadcaster's Headers
+	Timeout          time.Duration     // (Optional) Request timeout for this builder, defaults to the broadcaster's Timeout
+	Signer           Signer            // (Optional) Signer for this builder, instead of the one passed to the broadcast method
+	DisableSignature bool              // (Optional) Don't send the X-Flashbots-Signature header to this builder
+	Methods          []string          // (Optional) Methods to send to this builder, defaults to all methods
+	RateLimiter      *RateLimiter      // (Optional) Limits the requests to this builder, must not be shared with other builders
+
+	// (Optional) Transform adjusts the params of a request for this builder, e.g. to remove fields it doesn't support.
+	// It receives a copy of the params slice, but the params themselves may be shared with other builders and must not be modified in place.
+	Transform func(method string, params []interface{}) ([]interface{}, error)
+}
+
+// name returns the name of the builder, or its URL if it has none
+func (endpoint BuilderEndpoint) name() string {
+	if endpoint.Name != "" {
+		return endpoint.Name
+	}
+	return endpoint.URL
+}
+
+// acceptsMethod returns whether requests of the given method should be sent to this builder
+func (endpoint BuilderEndpoint) acceptsMethod(method string) bool {
+	if len(endpoint.Methods) == 0 {
+		return true
+	}
+	for _, m := range endpoint.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+type BuilderBroadcastRPC struct {
+	endpoints []BuilderEndpoint
+	client    httpClient
+	log       logger
+	Debug     bool
+	Headers   map[string]string // Additional headers to send with the request
+	Timeout   time.Duration
+	Policy    BroadcastPolicy
+
+	// (Optional) Limits the broadcasts as a whole, each broadcast takes a token. Builders are limited with
+	// BuilderEndpoint.RateLimiter or WithBuilderRateLimit.
+	RateLimiter *RateLimiter
+
+	middleware       []Middleware
+	builderRateLimit *builderRateLimit
+}
+
+// builderRateLimit is the default limit of builders without their own RateLimiter, see WithBuilderRateLimit
+type builderRateLimit struct {
+	limit   RateLimit
+	options []func(limiter *RateLimiter)
+}
+
+// NewBuilderBroadcastRPC create broadcaster rpc client with given url. Builders which need their own configuration can be
+// added with WithBuilderEndpoints.
+func NewBuilderBroadcastRPC(urls []string, options ...func(rpc *BuilderBroadcastRPC)) *BuilderBroadcastRPC {
+	rpc := &BuilderBroadcastRPC{
+		log:     log.New(os.Stderr, "", log.LstdFlags),
+		Headers: make(map[string]string),
+		Timeout: 30 * time.Second,
+	}
+	for _, url := range urls {
+		rpc.endpoints = append(rpc.endpoints, BuilderEndpoint{URL: url})
+	}
+	for _, option := range options {
+		option(rpc)
+	}
+	if rpc.builderRateLimit != nil {
+		for i := range rpc.endpoints {
+			if rpc.endpoints[i].RateLimiter == nil {
+				rpc.endpoints[i].RateLimiter = NewRateLimiter(rpc.builderRateLimit.limit, rpc.builderRateLimit.options...)
+			}
+		}
+	}
+	// Timeouts are set per request, as they can differ between builders
+	rpc.client = &http.Client{}
+	return rpc
+}
+
+// https://docs.flashbots.net/flashbots-auction/searchers/advanced/rpc-endpoint/#eth_sendbundle
+func (broadcaster *BuilderBroadcastRPC) BroadcastBundle(privKey *ecdsa.PrivateKey, param FlashbotsSendBundleRequest) []BuilderBroadcastResponse {
+	return broadcaster.BroadcastBundleContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// BroadcastBundleContext is like BroadcastBundle but takes a context. Cancelling the context aborts all outstanding builder requests.
+func (broadcaster *BuilderBroadcastRPC) BroadcastBundleContext(ctx context.Context, signer Signer, param FlashbotsSendBundleRequest) []BuilderBroadcastResponse {
+	requestResponses := broadcaster.broadcastRequest(ctx, "eth_sendBundle", signer, param)
+
+	responses := make([]BuilderBroadcastResponse, len(requestResponses))
+	for i, requestResponse := range requestResponses {
+		responses[i].BuilderBroadcastResult = requestResponse.decode(&responses[i].BundleResponse)
+	}
+
+	return responses
+}
+
+// BroadcastCancelBundle cancels a bundle at all builders, see FlashbotsCancelBundle
+func (broadcaster *BuilderBroadcastRPC) BroadcastCancelBundle(privKey *ecdsa.PrivateKey, param FlashbotsCancelBundleRequest) []BuilderBroadcastCancelBundleResponse {
+	return broadcaster.BroadcastCancelBundleContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// BroadcastCancelBundleContext is like BroadcastCancelBundle but takes a context for cancellation and deadlines.
+func (broadcaster *BuilderBroadcastRPC) BroadcastCancelBundleContext(ctx context.Context, signer Signer, param FlashbotsCancelBundleRequest) []BuilderBroadcastCancelBundleResponse {
+	requestResponses := broadcaster.broadcastRequest(ctx, "eth_cancelBundle", signer, param)
+
+	responses := make([]BuilderBroadcastCancelBundleResponse, len(requestResponses))
+	for i, requestResponse := range requestResponses {
+		responses[i].BuilderBroadcastResult = requestResponse.BuilderBroadcastResult
+	}
+
+	return responses
+}
+
+// BroadcastCallBundle simulates a bundle at all builders, e.g. to compare the results, see FlashbotsCallBundle
+func (broadcaster *BuilderBroadcastRPC) BroadcastCallBundle(privKey *ecdsa.PrivateKey, param FlashbotsCallBundleParam) []BuilderBroadcastCallBundleResponse {
+	return broadcaster.BroadcastCallBundleContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// BroadcastCallBundleContext is like BroadcastCallBundle but takes a context for cancellation and deadlines.
+func (broadcaster *BuilderBroadcastRPC) BroadcastCallBundleContext(ctx context.Context, signer Signer, param FlashbotsCallBundleParam) []BuilderBroadcastCallBundleResponse {
+	requestResponses := broadcaster.broadcastRequest(ctx, "eth_callBundle", signer, param)
+
+	responses := make([]BuilderBroadcastCallBundleResponse, len(requestResponses))
+	for i, requestResponse := range requestResponses {
+		responses[i].BuilderBroadcastResult = requestResponse.decode(&responses[i].CallBundleResponse)
+	}
+
+	return responses
+}
+
+// BroadcastMevSendBundle sends a MEV-Share bundle to all builders, see MevSendBundle
+func (broadcaster *BuilderBroadcastRPC) BroadcastMevSendBundle(privKey *ecdsa.PrivateKey, param MevSendBundleRequest) []BuilderBroadcastMevSendBundleResponse {
+	return broadcaster.BroadcastMevSendBundleContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// BroadcastMevSendBundleContext is like BroadcastMevSendBundle but takes a context for cancellation and deadlines.
+func (broadcaster *BuilderBroadcastRPC) BroadcastMevSendBundleContext(ctx context.Context, signer Signer, param MevSendBundleRequest) []BuilderBroadcastMevSendBundleResponse {
+	requestResponses := broadcaster.broadcastRequest(ctx, "mev_sendBundle", signer, param)
+
+	responses := make([]BuilderBroadcastMevSendBundleResponse, len(requestResponses))
+	for i, requestResponse := range requestResponses {
+		responses[i].BuilderBroadcastResult = requestResponse.decode(&responses[i].BundleResponse)
+	}
+
+	return responses
+}
+
+// BroadcastPrivateTransaction sends a private transaction to all builders, see FlashbotsSendPrivateTransaction
+func (broadcaster *BuilderBroadcastRPC) BroadcastPrivateTransaction(privKey *ecdsa.PrivateKey, param FlashbotsSendPrivateTransactionRequest) []BuilderBroadcastPrivateTransactionResponse {
+	return broadcaster.BroadcastPrivateTransactionContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// BroadcastPrivateTransactionContext is like BroadcastPrivateTransaction but takes a context for cancellation and deadlines.
+func (broadcaster *BuilderBroadcastRPC) BroadcastPrivateTransactionContext(ctx context.Context, signer Signer, param FlashbotsSendPrivateTransactionRequest) []BuilderBroadcastPrivateTransactionResponse {
+	requestResponses := broadcaster.broadcastRequest(ctx, "eth_sendPrivateTransaction", signer, param)
+
+	responses := make([]BuilderBroadcastPrivateTransactionResponse, len(requestResponses))
+	for i, requestResponse := range requestResponses {
+		responses[i].BuilderBroadcastResult = requestResponse.decode(&responses[i].TxHash)
+	}
+
+	return responses
+}
+
+// BroadcastCancelPrivateTransaction cancels a private transaction at all builders, see FlashbotsCancelPrivateTransaction
+func (broadcaster *BuilderBroadcastRPC) BroadcastCancelPrivateTransaction(privKey *ecdsa.PrivateKey, param FlashbotsCancelPrivateTransactionRequest) []BuilderBroadcastCancelPrivateTransactionResponse {
+	return broadcaster.BroadcastCancelPrivateTransactionContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// BroadcastCancelPrivateTransactionContext is like BroadcastCancelPrivateTransaction but takes a context for cancellation and deadlines.
+func (broadcaster *BuilderBroadcastRPC) BroadcastCancelPrivateTransactionContext(ctx context.Context, signer Signer, param FlashbotsCancelPrivateTransactionRequest) []BuilderBroadcastCancelPrivateTransactionResponse {
+	requestResponses := broadcaster.broadcastRequest(ctx, "eth_cancelPrivateTransaction", signer, param)
+
+	responses := make([]BuilderBroadcastCancelPrivateTransactionResponse, len(requestResponses))
+	for i, requestResponse := range requestResponses {
+		responses[i].BuilderBroadcastResult = requestResponse.decode(&responses[i].Cancelled)
+	}
+
+	return responses
+}
+
+// BroadcastRawTransaction sends a signed transaction to all builders with eth_sendRawTransaction. The request is signed
+// like all broadcast requests, builders which don't expect a signature can be configured with DisableSignature.
+func (broadcaster *BuilderBroadcastRPC) BroadcastRawTransaction(privKey *ecdsa.PrivateKey, data string) []BuilderBroadcastRawTransactionResponse {
+	return broadcaster.BroadcastRawTransactionContext(context.Background(), NewPrivateKeySigner(privKey), data)
+}
+
+// BroadcastRawTransactionContext is like BroadcastRawTransaction but takes a context for cancellation and deadlines.
+func (broadcaster *BuilderBroadcastRPC) BroadcastRawTransactionContext(ctx context.Context, signer Signer, data string) []BuilderBroadcastRawTransactionResponse {
+	requestResponses := broadcaster.broadcastRequest(ctx, "eth_sendRawTransaction", signer, data)
+
+	responses := make([]BuilderBroadcastRawTransactionResponse, len(requestResponses))
+	for i, requestResponse := range requestResponses {
+		responses[i].BuilderBroadcastResult = requestResponse.decode(&responses[i].TxHash)
+	}
+
+	return responses
+}
+
+type broadcastRequestResponse struct {
+	BuilderBroadcastResult
+	Msg json.RawMessage
+}
+
+// decode unmarshals the result of a successful request into v and returns the result of the builder
+func (r broadcastRequestResponse) decode(v interface{}) BuilderBroadcastResult {
+	result := r.BuilderBroadcastResult
+	if result.Err == nil {
+		result.Err = json.Unmarshal(r.Msg, v)
+	}
+	return result
+}
+
+type indexedBroadcastResponse struct {
+	index    int
+	response broadcastRequestResponse
+}
+
+// broadcastRequest sends the request concurrently to all builders which accept the method, and returns according to the broadcast policy.
+// It returns one response per builder, in the order of the builders. Builders which haven't answered yet have ErrBroadcastPending.
+func (broadcaster *BuilderBroadcastRPC) broadcastRequest(ctx context.Context, method string, signer Signer, params ...interface{}) []broadcastRequestResponse {
+	start := time.Now()
+	endpoints := []BuilderEndpoint{}
+	for _, endpoint := range broadcaster.endpoints {
+		if endpoint.acceptsMethod(method) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	responses := make([]broadcastRequestResponse, len(endpoints))
+	for i, endpoint := range endpoints {
+		responses[i].URL = endpoint.URL
+		responses[i].Name = endpoint.name()
+		responses[i].Err = ErrBroadcastPending
+	}
+
+	if broadcaster.RateLimiter != nil {
+		if err := broadcaster.RateLimiter.Wait(ctx, method); err != nil {
+			for i := range responses {
+				responses[i].Err = err
+			}
+			return responses
+		}
+	}
+
+	// Most builders get the same request, so it's only signed once
+	body, signature, err := newSignedRequest(ctx, method, signer, params)
+	if err != nil {
+		for i := range responses {
+			responses[i].Err = err
+		}
+		return responses
+	}
+
+	policy := broadcaster.Policy
+	if policy.CancelRemaining {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+	}
+
+	var deadline <-chan time.Time
+	if policy.Deadline > 0 {
+		timer := time.NewTimer(policy.Deadline)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	// Iterate over the builders and send requests concurrently. The channel is buffered, so requests which
+	// complete after an early return don't block.
+	responseCh := make(chan indexedBroadcastResponse, len(responses))
+	for i, endpoint := range endpoints {
+		go func(i int, endpoint BuilderEndpoint) {
+			response := broadcastRequestResponse{}
+			response.URL = endpoint.URL
+			response.Name = endpoint.name()
+			response.Msg, response.StatusCode, response.Err = broadcaster.sendToEndpoint(ctx, endpoint, method, signer, params, body, signature)
+			response.Latency = time.Since(start)
+			responseCh <- indexedBroadcastResponse{index: i, response: response}
+		}(i, endpoint)
+	}
+
+	successes := 0
+	for received := 0; received < len(responses); received++ {
+		select {
+		case r := <-responseCh:
+			responses[r.index] = r.response
+			if r.response.Err == nil {
+				successes++
+			}
+		case <-deadline:
+			return broadcaster.pendingLatency(responses, start)
+		}
+
+		if policy.MinSuccesses > 0 && successes >= policy.MinSuccesses {
+			return broadcaster.pendingLatency(responses, start)
+		}
+	}
+
+	return responses
+}
+
+// firstSuccess returns the index of the first builder which accepted the request, in the order of the builders, or
+// ErrAllBuildersFailed with the error of the first builder if none did
+func firstSuccess(results []BuilderBroadcastResult) (int, error) {
+	for i, result := range results {
+		if result.Err == nil {
+			return i, nil
+		}
+	}
+	if len(results) > 0 {
+		return -1, fmt.Errorf("%w: %s: %v", ErrAllBuildersFailed, results[0].Name, results[0].Err)
+	}
+	return -1, ErrAllBuildersFailed
+}
+
+// newSignedRequest creates the JSON-RPC request body and its Flashbots signature. Without a signer, the signature is empty.
+func newSignedRequest(ctx context.Context, method string, signer Signer, params []interface{}) ([]byte, string, error) {
+	request := rpcRequest{
+		ID:      1,
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if signer == nil {
+		return body, "", nil
+	}
+
+	signature, err := flashbotsSignature(ctx, body, signer)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, signature, nil
+}
+
+// sendToEndpoint applies the configuration of the builder and sends the request. body and signature are the
+// request for builders without their own signer or transform.
+func (broadcaster *BuilderBroadcastRPC) sendToEndpoint(ctx context.Context, endpoint BuilderEndpoint, method string, signer Signer, params []interface{}, body []byte, signature string) (json.RawMessage, int, error) {
+	if endpoint.Transform != nil || endpoint.Signer != nil || endpoint.DisableSignature {
+		if endpoint.Transform != nil {
+			var err error
+			params, err = endpoint.Transform(method, append([]interface{}{}, params...))
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		if endpoint.Signer != nil {
+			signer = endpoint.Signer
+		}
+		if endpoint.DisableSignature {
+			signer = nil
+		}
+
+		var err error
+		body, signature, err = newSignedRequest(ctx, method, signer, params)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	timeout := broadcaster.Timeout
+	if endpoint.Timeout > 0 {
+		timeout = endpoint.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if endpoint.RateLimiter != nil {
+		if err := endpoint.RateLimiter.Wait(ctx, method); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return broadcaster.send(ctx, endpoint, method, body, signature, signer)
+}
+
+// pendingLatency sets the latency of builders which haven't answered yet to the time waited for them
+func (broadcaster *BuilderBroadcastRPC) pendingLatency(responses []broadcastRequestResponse, start time.Time) []broadcastRequestResponse {
+	for i := range responses {
+		if responses[i].Err == ErrBroadcastPending {
+			responses[i].Latency = time.Since(start)
+		}
+	}
+	return responses
+}
+
+// send posts the request to a single builder and returns the result of the JSON-RPC response
+func (broadcaster *BuilderBroadcastRPC) send(ctx context.Context, endpoint BuilderEndpoint, method string, body []byte, signature string, signer Signer) (json.RawMessage, int, error) {
+	req := newRoundTripRequest(ctx, method, endpoint.URL, body, signature, signer)
+	for k, v := range broadcaster.Headers {
+		req.Header.Add(k, v)
+	}
+	for k, v := range endpoint.Headers {
+		req.Header.Set(k, v)
+	}
+
+	response, err := chainMiddleware(broadcaster.middleware, httpRoundTrip(broadcaster.client, 0))(ctx, req)
+	if response == nil {
+		return nil, 0, err
+	}
+	if err != nil {
+		return nil, response.StatusCode, err
+	}
+	data := response.Body
+
+	if broadcaster.Debug {
+		broadcaster.log.Println(fmt.Sprintf("%s %s\nRequest: %s\nSignature: %s\nResponse: %s\n", method, endpoint.name(), body, signature, data))
+	}
+
+	// On error, response looks like this instead of JSON-RPC: {"error":"block param must be a hex int"}
+	result, err := httpResponse{method: method, endpoint: endpoint.URL, statusCode: response.StatusCode, body: data}.result(true)
+	return result, response.StatusCode, err
+}
+
+// WithBroadcastMiddleware add middleware for the requests to all builders, see Middleware. The URL of the request
+// tells the builder.
+func WithBroadcastMiddleware(middleware ...Middleware) func(rpc *BuilderBroadcastRPC) {
+	return func(rpc *BuilderBroadcastRPC) {
+		rpc.middleware = append(rpc.middleware, middleware...)
+	}
+}
+
+// WithBuilderRateLimit give every builder without its own BuilderEndpoint.RateLimiter a rate limiter with this limit,
+// see NewRateLimiter
+func WithBuilderRateLimit(limit RateLimit, options ...func(limiter *RateLimiter)) func(rpc *BuilderBroadcastRPC) {
+	return func(rpc *BuilderBroadcastRPC) {
+		rpc.builderRateLimit = &builderRateLimit{limit: limit, options: options}
+	}
+}
+
+// WithBroadcastRateLimiter limit the broadcasts as a whole, see BuilderBroadcastRPC.RateLimiter
+func WithBroadcastRateLimiter(limiter *RateLimiter) func(rpc *BuilderBroadcastRPC) {
+	return func(rpc *BuilderBroadcastRPC) {
+		rpc.RateLimiter = limiter
+	}
+}
+
+// WithBroadcastPolicy set when broadcasts return, see BroadcastPolicy
+func WithBroadcastPolicy(policy BroadcastPolicy) func(rpc *BuilderBroadcastRPC) {
+	return func(rpc *BuilderBroadcastRPC) {
+		rpc.Policy = policy
+	}
+}
+
+// WithBuilderEndpoints add builders with their own configuration, in addition to the plain urls
+func WithBuilderEndpoints(endpoints ...BuilderEndpoint) func(rpc *BuilderBroadcastRPC) {
+	return func(rpc *BuilderBroadcastRPC) {
+		rpc.endpoints = append(rpc.endpoints, endpoints...)
+	}
+}
@@ -0,0 +1,292 @@
+package flashbotsrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestBroadcastBundleAttribution(t *testing.T) {
+	okServer := flashbotstest.NewNode()
+	defer okServer.Close()
+	okServer.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	relayErrorServer := flashbotstest.NewNode()
+	defer relayErrorServer.Close()
+	relayErrorServer.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadRequest, Message: "block param must be a hex int"})
+	rpcErrorServer := flashbotstest.NewNode()
+	defer rpcErrorServer.Close()
+	rpcErrorServer.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{Code: flashbotstest.CodeServerError, Message: "bundle rejected"})
+	badGatewayServer := flashbotstest.NewNode()
+	defer badGatewayServer.Close()
+	badGatewayServer.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadGateway, Body: "<html>bad gateway</html>"})
+	closedServer := flashbotstest.NewNode()
+	closedServer.Close()
+
+	urls := []string{okServer.URL, relayErrorServer.URL, rpcErrorServer.URL, badGatewayServer.URL, closedServer.URL}
+	broadcaster := flashbotsrpc.NewBuilderBroadcastRPC(urls)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	results := broadcaster.BroadcastBundle(key, flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.Len(t, results, len(urls))
+	for i, result := range results {
+		require.Equal(t, urls[i], result.URL)
+		require.Greater(t, result.Latency.Nanoseconds(), int64(0))
+	}
+
+	require.NoError(t, results[0].Err)
+	require.Equal(t, http.StatusOK, results[0].StatusCode)
+	require.Equal(t, "0x1234", results[0].BundleResponse.BundleHash)
+
+	require.True(t, errors.Is(results[1].Err, flashbotsrpc.ErrRelayErrorResponse))
+	require.Contains(t, results[1].Err.Error(), "block param must be a hex int")
+	require.Equal(t, http.StatusBadRequest, results[1].StatusCode)
+
+	require.True(t, errors.Is(results[2].Err, flashbotsrpc.ErrRelayErrorResponse))
+	require.Contains(t, results[2].Err.Error(), "bundle rejected")
+
+	require.Error(t, results[3].Err)
+	require.Equal(t, http.StatusBadGateway, results[3].StatusCode)
+
+	require.Error(t, results[4].Err)
+	require.Equal(t, 0, results[4].StatusCode)
+}
+
+func TestBroadcastPolicy(t *testing.T) {
+	okServer := flashbotstest.NewNode()
+	defer okServer.Close()
+	okServer.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	failServer := flashbotstest.NewNode()
+	defer failServer.Close()
+	failServer.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{Message: "bundle rejected"})
+
+	release := make(chan struct{})
+	slowCancelled := make(chan struct{}, 1)
+	// The slow builder never answers, and reports when the broadcast gave up on it
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body) // the server only notices the client going away after the body was read
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			slowCancelled <- struct{}{}
+		}
+	}))
+	defer slowServer.Close()
+	defer close(release)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	urls := []string{slowServer.URL, failServer.URL, okServer.URL, okServer.URL}
+	args := flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"}
+
+	t.Run("min successes", func(t *testing.T) {
+		broadcaster := flashbotsrpc.NewBuilderBroadcastRPC(urls, flashbotsrpc.WithBroadcastPolicy(flashbotsrpc.BroadcastPolicy{MinSuccesses: 2, CancelRemaining: true}))
+		results := broadcaster.BroadcastBundle(key, args)
+		require.Len(t, results, len(urls))
+		require.Equal(t, slowServer.URL, results[0].URL)
+		require.True(t, errors.Is(results[0].Err, flashbotsrpc.ErrBroadcastPending))
+		// The failing builder may answer before or after the broadcast returned, but never counts as a success
+		require.Equal(t, failServer.URL, results[1].URL)
+		require.True(t, errors.Is(results[1].Err, flashbotsrpc.ErrRelayErrorResponse) || errors.Is(results[1].Err, flashbotsrpc.ErrBroadcastPending), results[1].Err)
+		require.NoError(t, results[2].Err)
+		require.NoError(t, results[3].Err)
+
+		select {
+		case <-slowCancelled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("remaining request was not cancelled")
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		broadcaster := flashbotsrpc.NewBuilderBroadcastRPC(urls, flashbotsrpc.WithBroadcastPolicy(flashbotsrpc.BroadcastPolicy{Deadline: 100 * time.Millisecond}))
+		results := broadcaster.BroadcastBundle(key, args)
+		require.Len(t, results, len(urls))
+		require.True(t, errors.Is(results[0].Err, flashbotsrpc.ErrBroadcastPending))
+		require.GreaterOrEqual(t, results[0].Latency, 100*time.Millisecond)
+		require.True(t, errors.Is(results[1].Err, flashbotsrpc.ErrRelayErrorResponse))
+		require.NoError(t, results[2].Err)
+		require.Equal(t, "0x1234", results[2].BundleResponse.BundleHash)
+	})
+
+	t.Run("min successes not reached", func(t *testing.T) {
+		broadcaster := flashbotsrpc.NewBuilderBroadcastRPC(urls[1:], flashbotsrpc.WithBroadcastPolicy(flashbotsrpc.BroadcastPolicy{MinSuccesses: 3}))
+		results := broadcaster.BroadcastBundle(key, args)
+		require.Len(t, results, 3)
+		require.True(t, errors.Is(results[0].Err, flashbotsrpc.ErrRelayErrorResponse))
+		require.NoError(t, results[1].Err)
+		require.NoError(t, results[2].Err)
+	})
+}
+
+func TestBuilderEndpoints(t *testing.T) {
+	builders := make(map[string]*flashbotstest.Node)
+	for _, name := range []string{"plain", "auth", "signer", "cancel-only", "transform", "slow"} {
+		builders[name] = flashbotstest.NewNode()
+		defer builders[name].Close()
+		builders[name].SetResult(flashbotstest.AnyMethod, flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	}
+	builders["slow"].SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusGatewayTimeout, Delay: time.Second})
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	builderKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	broadcaster := flashbotsrpc.NewBuilderBroadcastRPC([]string{builders["plain"].URL}, flashbotsrpc.WithBuilderEndpoints(
+		flashbotsrpc.BuilderEndpoint{
+			URL:              builders["auth"].URL,
+			Name:             "auth-builder",
+			Headers:          map[string]string{"Authorization": "secret"},
+			DisableSignature: true,
+		},
+		flashbotsrpc.BuilderEndpoint{
+			URL:    builders["signer"].URL,
+			Signer: flashbotsrpc.NewPrivateKeySigner(builderKey),
+		},
+		flashbotsrpc.BuilderEndpoint{
+			URL:     builders["cancel-only"].URL,
+			Methods: []string{"eth_cancelBundle"},
+		},
+		flashbotsrpc.BuilderEndpoint{
+			URL: builders["transform"].URL,
+			Transform: func(method string, params []interface{}) ([]interface{}, error) {
+				bundle := params[0].(flashbotsrpc.FlashbotsSendBundleRequest)
+				bundle.ReplacementUUID = ""
+				params[0] = bundle
+				return params, nil
+			},
+		},
+		flashbotsrpc.BuilderEndpoint{
+			URL:     builders["slow"].URL,
+			Timeout: 50 * time.Millisecond,
+		},
+	))
+
+	results := broadcaster.BroadcastBundle(key, flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1", ReplacementUUID: "2f2a2e5e-6e5c-4a6f-9a4e-7b4c2d0c8e11"})
+	require.Len(t, results, 5)
+	require.Equal(t, builders["plain"].URL, results[0].Name)
+	require.Equal(t, "auth-builder", results[1].Name)
+	require.Equal(t, builders["auth"].URL, results[1].URL)
+	require.Equal(t, builders["signer"].URL, results[2].URL)
+	require.Equal(t, builders["transform"].URL, results[3].URL)
+	require.Equal(t, builders["slow"].URL, results[4].URL)
+	for _, result := range results[:4] {
+		require.NoError(t, result.Err)
+	}
+	require.True(t, errors.Is(results[4].Err, context.DeadlineExceeded))
+	require.Empty(t, builders["cancel-only"].Requests())
+
+	keyAddress := crypto.PubkeyToAddress(key.PublicKey)
+	plain := builders["plain"].Requests()
+	require.Len(t, plain, 1)
+	require.Equal(t, keyAddress, plain[0].Signer)
+	require.Empty(t, plain[0].Header.Get("Authorization"))
+	require.Contains(t, string(plain[0].Params), "replacementUuid")
+
+	auth := builders["auth"].Requests()
+	require.Len(t, auth, 1)
+	require.Empty(t, auth[0].Header.Get("X-Flashbots-Signature"))
+	require.Equal(t, "secret", auth[0].Header.Get("Authorization"))
+
+	require.Equal(t, crypto.PubkeyToAddress(builderKey.PublicKey), builders["signer"].Requests()[0].Signer)
+
+	transform := builders["transform"].Requests()
+	require.Len(t, transform, 1)
+	require.Equal(t, keyAddress, transform[0].Signer)
+	require.NotContains(t, string(transform[0].Params), "replacementUuid")
+}
+
+func TestBroadcastMethods(t *testing.T) {
+	server := flashbotstest.NewNode()
+	defer server.Close()
+	server.Handle("eth_sendPrivateTransaction", func(params []json.RawMessage) (interface{}, error) {
+		require.Equal(t, "0x01", gjson.GetBytes(params[0], "tx").String())
+		return "0x1111", nil
+	})
+	server.Handle("eth_cancelPrivateTransaction", func(params []json.RawMessage) (interface{}, error) {
+		require.Equal(t, "0x1111", gjson.GetBytes(params[0], "txHash").String())
+		return true, nil
+	})
+	server.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (interface{}, error) {
+		require.JSONEq(t, `"0x01"`, string(params[0]))
+		return "0x2222", nil
+	})
+	server.Handle("eth_callBundle", func(params []json.RawMessage) (interface{}, error) {
+		require.Equal(t, "0x01", gjson.GetBytes(params[0], "txs.0").String())
+		return json.RawMessage(`{"bundleHash":"0x3333","coinbaseDiff":"100","results":[{"txHash":"0x0000000000000000000000000000000000000000000000000000000000004444","gasUsed":21000}]}`), nil
+	})
+	server.Handle("mev_sendBundle", func(params []json.RawMessage) (interface{}, error) {
+		require.Equal(t, "0x10", gjson.GetBytes(params[0], "inclusion.block").String())
+		return json.RawMessage(`{"bundleHash":"0x0000000000000000000000000000000000000000000000000000000000005555"}`), nil
+	})
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	broadcaster := flashbotsrpc.NewBuilderBroadcastRPC([]string{server.URL, server.URL})
+
+	privateTxResults := broadcaster.BroadcastPrivateTransaction(key, flashbotsrpc.FlashbotsSendPrivateTransactionRequest{Tx: "0x01"})
+	require.Len(t, privateTxResults, 2)
+	for _, result := range privateTxResults {
+		require.NoError(t, result.Err)
+		require.Equal(t, "0x1111", result.TxHash)
+	}
+
+	cancelResults := broadcaster.BroadcastCancelPrivateTransaction(key, flashbotsrpc.FlashbotsCancelPrivateTransactionRequest{TxHash: "0x1111"})
+	require.Len(t, cancelResults, 2)
+	for _, result := range cancelResults {
+		require.NoError(t, result.Err)
+		require.True(t, result.Cancelled)
+	}
+
+	rawTxResults := broadcaster.BroadcastRawTransaction(key, "0x01")
+	require.Len(t, rawTxResults, 2)
+	for _, result := range rawTxResults {
+		require.NoError(t, result.Err)
+		require.Equal(t, "0x2222", result.TxHash)
+	}
+
+	callBundleResults := broadcaster.BroadcastCallBundle(key, flashbotsrpc.FlashbotsCallBundleParam{Txs: []string{"0x01"}, BlockNumber: "0x1", StateBlockNumber: "latest"})
+	require.Len(t, callBundleResults, 2)
+	for _, result := range callBundleResults {
+		require.NoError(t, result.Err)
+		require.Equal(t, "0x3333", result.CallBundleResponse.BundleHash)
+		require.Len(t, result.CallBundleResponse.Results, 1)
+	}
+
+	mevResults := broadcaster.BroadcastMevSendBundle(key, flashbotsrpc.MevSendBundleRequest{Version: "v0.1", Inclusion: flashbotsrpc.MevBundleInclusion{BlockNumber: 16}, Body: []flashbotsrpc.MevBundleBody{{Tx: "0x01"}}})
+	require.Len(t, mevResults, 2)
+	for _, result := range mevResults {
+		require.NoError(t, result.Err)
+		require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000005555", result.BundleResponse.BundleHash.Hex())
+	}
+
+	for _, request := range server.Requests() {
+		require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), request.Signer, request.Method)
+	}
+}
+
+func TestFirstSuccess(t *testing.T) {
+	failed := flashbotsrpc.BuilderBroadcastResult{Name: "a", Err: flashbotsrpc.ErrRelayErrorResponse}
+	i, err := flashbotsrpc.FirstSuccess([]flashbotsrpc.BuilderBroadcastResult{failed, {Name: "b"}, {Name: "c"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+
+	_, err = flashbotsrpc.FirstSuccess([]flashbotsrpc.BuilderBroadcastResult{failed, {Name: "b", Err: flashbotsrpc.ErrBroadcastPending}})
+	require.True(t, errors.Is(err, flashbotsrpc.ErrAllBuildersFailed))
+	require.Contains(t, err.Error(), "a: "+flashbotsrpc.ErrRelayErrorResponse.Error())
+
+	_, err = flashbotsrpc.FirstSuccess(nil)
+	require.Equal(t, flashbotsrpc.ErrAllBuildersFailed, err)
+}
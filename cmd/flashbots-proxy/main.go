@@ -0,0 +1,129 @@
+// flashbots-proxy accepts unsigned JSON-RPC requests, signs Flashbots methods with a configured key and sends them to the
+// relay or to builders, and forwards all other methods to an Ethereum node. Tooling which can't sign requests can use it
+// to share one reputation key without holding it.
+//
+// With -router, it serves a PrivateTxRouter for wallets instead: eth_sendRawTransaction is sent as a private transaction,
+// and all other methods are forwarded to the node.
+//
+// The signer is configured with one of:
+//
+//	FLASHBOTS_SIGNER_KEY=<hex private key> flashbots-proxy
+//	FLASHBOTS_KEYSTORE_PASSPHRASE=<passphrase> flashbots-proxy -keystore <path>
+//	flashbots-proxy -remote-signer <url> -signer-address <address>
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+)
+
+var (
+	listenAddr    = flag.String("listen", "127.0.0.1:18545", "address to listen on")
+	relayURL      = flag.String("relay", "https://relay.flashbots.net", "relay URL")
+	builderURLs   = flag.String("builders", "", "comma separated builder URLs to broadcast to, instead of the relay")
+	nodeURL       = flag.String("node", "", "Ethereum node URL for all other methods")
+	keystorePath  = flag.String("keystore", "", "keystore file of the signer key, the passphrase is read from FLASHBOTS_KEYSTORE_PASSPHRASE")
+	remoteSigner  = flag.String("remote-signer", "", "URL of a remote signing service")
+	signerAddress = flag.String("signer-address", "", "address of the remote signer key")
+	origins       = flag.String("allowed-origins", "", "comma separated origins of websites which may send requests, all browser requests are rejected by default")
+	routerMode    = flag.Bool("router", false, "serve a private transaction router for wallets, requires -node")
+	debug         = flag.Bool("debug", false, "log requests and responses")
+)
+
+func main() {
+	flag.Parse()
+
+	signer, err := newSigner()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	handler, err := newHandler(signer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("signing as %s, listening on %s", signer.Address().Hex(), *listenAddr)
+	server := &http.Server{
+		Addr:              *listenAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Fatal(server.ListenAndServe())
+}
+
+func newHandler(signer flashbotsrpc.Signer) (http.Handler, error) {
+	var broadcaster *flashbotsrpc.BuilderBroadcastRPC
+	if *builderURLs != "" {
+		broadcaster = flashbotsrpc.NewBuilderBroadcastRPC(strings.Split(*builderURLs, ","))
+	}
+
+	if *routerMode {
+		if *nodeURL == "" {
+			return nil, errors.New("-router requires -node")
+		}
+		options := []func(router *flashbotsrpc.PrivateTxRouter){}
+		if broadcaster != nil {
+			options = append(options, flashbotsrpc.WithPrivateTxRouterBroadcaster(broadcaster))
+		}
+		if *origins != "" {
+			options = append(options, flashbotsrpc.WithPrivateTxRouterAllowedOrigins(strings.Split(*origins, ",")...))
+		}
+		router, err := flashbotsrpc.NewPrivateTxRouter(flashbotsrpc.New(*nodeURL), flashbotsrpc.New(*relayURL), signer, options...)
+		if err != nil {
+			return nil, err
+		}
+		router.Debug = *debug
+		return router, nil
+	}
+
+	options := []func(proxy *flashbotsrpc.ProxyHandler){}
+	if broadcaster != nil {
+		options = append(options, flashbotsrpc.WithProxyBroadcaster(broadcaster))
+	}
+	if *nodeURL != "" {
+		options = append(options, flashbotsrpc.WithProxyNode(flashbotsrpc.New(*nodeURL)))
+	}
+	if *origins != "" {
+		options = append(options, flashbotsrpc.WithProxyAllowedOrigins(strings.Split(*origins, ",")...))
+	}
+
+	proxy := flashbotsrpc.NewProxyHandler(flashbotsrpc.New(*relayURL), signer, options...)
+	proxy.Debug = *debug
+	return proxy, nil
+}
+
+func newSigner() (flashbotsrpc.Signer, error) {
+	switch {
+	case *remoteSigner != "":
+		if !common.IsHexAddress(*signerAddress) {
+			return nil, errors.New("-signer-address must be set to the address of the remote signer key")
+		}
+		return flashbotsrpc.NewRemoteSigner(*remoteSigner, common.HexToAddress(*signerAddress)), nil
+	case *keystorePath != "":
+		signer, err := flashbotsrpc.NewKeystoreSigner(*keystorePath, os.Getenv("FLASHBOTS_KEYSTORE_PASSPHRASE"))
+		if err != nil {
+			return nil, err
+		}
+		return signer, nil
+	}
+
+	key := os.Getenv("FLASHBOTS_SIGNER_KEY")
+	if key == "" {
+		return nil, errors.New("no signer: set FLASHBOTS_SIGNER_KEY, -keystore or -remote-signer")
+	}
+	privKey, err := crypto.HexToECDSA(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return flashbotsrpc.NewPrivateKeySigner(privKey), nil
+}
@@ -0,0 +1,124 @@
+package flashbotsrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Errors of known relay and builder failures. A RelayError matches one of them with errors.Is, besides
+// ErrRelayErrorResponse. ErrRelayRateLimited is also matched by relay responses with HTTP status code 429.
+var (
+	ErrTxNotFound         = errors.New("tx not found")
+	ErrTxAlreadyCancelled = errors.New("tx was already cancelled")
+	ErrTxExpired          = errors.New("tx has already expired")
+	ErrInvalidBlockParam  = errors.New("invalid block param")
+	ErrBundleTooLarge     = errors.New("bundle too large")
+	ErrRelayRateLimited   = errors.New("rate limited by relay")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrSimulationFailed   = errors.New("simulation failed")
+)
+
+// RelayError is the error response of a relay or builder, either a relay error ({"error": "..."}) or a JSON-RPC error.
+// It matches ErrRelayErrorResponse and the error of the failure (e.g. ErrTxNotFound) with errors.Is, and can be
+// inspected with errors.As:
+//
+//	var relayErr *flashbotsrpc.RelayError
+//	if errors.As(err, &relayErr) {
+//		log.Println(relayErr.Endpoint, relayErr.StatusCode, string(relayErr.Body))
+//	}
+type RelayError struct {
+	Method     string          // Method of the request, comma separated for batches
+	Endpoint   string          // URL of the relay or builder
+	StatusCode int             // HTTP status code of the response
+	Code       int             // JSON-RPC error code, 0 for relay errors
+	Message    string          // Error message of the response
+	Data       json.RawMessage // Data of the JSON-RPC error, if any
+	Body       []byte          // Raw response body
+
+	// Kind is the error of the failure, e.g. ErrTxNotFound, or nil if it isn't known
+	Kind error
+}
+
+func (err *RelayError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrRelayErrorResponse, err.Message)
+}
+
+// Is matches ErrRelayErrorResponse and Kind
+func (err *RelayError) Is(target error) bool {
+	return target == ErrRelayErrorResponse || (err.Kind != nil && target == err.Kind)
+}
+
+// relayError returns the error for an error message of the response
+func (res httpResponse) relayError(code int, message string, data json.RawMessage) *RelayError {
+	return &RelayError{
+		Method:     res.method,
+		Endpoint:   res.endpoint,
+		StatusCode: res.statusCode,
+		Code:       code,
+		Message:    message,
+		Data:       data,
+		Body:       res.body,
+		Kind:       relayErrorKind(res.statusCode, message),
+	}
+}
+
+// errorResponse returns the error if the body is a relay error: {"error":"block param must be a hex int"}
+func (res httpResponse) errorResponse() error {
+	errorResp := new(RelayErrorResponse)
+	if err := json.Unmarshal(res.body, errorResp); err == nil && errorResp.Error != "" {
+		return res.relayError(0, errorResp.Error, nil)
+	}
+	return nil
+}
+
+// relayErrorMessages are the known error messages of relays and builders, in lower case
+var relayErrorMessages = map[string]error{
+	"tx not found":                                    ErrTxNotFound,
+	"transaction not found":                           ErrTxNotFound,
+	"tx was already cancelled":                        ErrTxAlreadyCancelled,
+	"tx already cancelled":                            ErrTxAlreadyCancelled,
+	"tx has already expired":                          ErrTxExpired,
+	"tx expired":                                      ErrTxExpired,
+	"block param must be a hex int":                   ErrInvalidBlockParam,
+	"invalid block param":                             ErrInvalidBlockParam,
+	"invalid blocknumber":                             ErrInvalidBlockParam,
+	"bundle too large":                                ErrBundleTooLarge,
+	"too many txs in bundle":                          ErrBundleTooLarge,
+	"rate limit exceeded":                             ErrRelayRateLimited,
+	"too many requests":                               ErrRelayRateLimited,
+	"invalid flashbots signature":                     ErrUnauthorized,
+	"missing flashbots signature header":              ErrUnauthorized,
+	"unauthorized":                                    ErrUnauthorized,
+	strings.ToLower(ErrMissingSignature.Error()):      ErrUnauthorized,
+	strings.ToLower(ErrInvalidSignature.Error()):      ErrUnauthorized,
+	strings.ToLower(ErrSignerNotAllowed.Error()):      ErrUnauthorized,
+	strings.ToLower(ErrSignerAddressMismatch.Error()): ErrUnauthorized,
+	"simulation failed":                               ErrSimulationFailed,
+	"simulation error":                                ErrSimulationFailed,
+}
+
+// relayErrorKind returns the known failure for the HTTP status code and message of a relay error, or nil. The status
+// code decides first, then the message has to be one of relayErrorMessages, optionally followed by ": <details>".
+func relayErrorKind(statusCode int, message string) error {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRelayRateLimited
+	case http.StatusRequestEntityTooLarge:
+		return ErrBundleTooLarge
+	}
+
+	message = strings.ToLower(strings.TrimSpace(message))
+	if kind, ok := relayErrorMessages[message]; ok {
+		return kind
+	}
+	if i := strings.Index(message, ":"); i > 0 {
+		return relayErrorMessages[message[:i]]
+	}
+	return nil
+}
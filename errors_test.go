@@ -0,0 +1,116 @@
+package flashbotsrpc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayErrorKind(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		message    string
+		kind       error
+	}{
+		{http.StatusBadRequest, "tx not found", flashbotsrpc.ErrTxNotFound},
+		{http.StatusBadRequest, "tx was already cancelled", flashbotsrpc.ErrTxAlreadyCancelled},
+		{http.StatusBadRequest, "tx has already expired", flashbotsrpc.ErrTxExpired},
+		{http.StatusBadRequest, "block param must be a hex int", flashbotsrpc.ErrInvalidBlockParam},
+		{http.StatusBadRequest, "bundle too large", flashbotsrpc.ErrBundleTooLarge},
+		{http.StatusRequestEntityTooLarge, "", flashbotsrpc.ErrBundleTooLarge},
+		{http.StatusTooManyRequests, "", flashbotsrpc.ErrRelayRateLimited},
+		{http.StatusBadRequest, "rate limit exceeded", flashbotsrpc.ErrRelayRateLimited},
+		{http.StatusForbidden, "invalid flashbots signature", flashbotsrpc.ErrUnauthorized},
+		{http.StatusOK, "signer is not allowed", flashbotsrpc.ErrUnauthorized},
+		{http.StatusOK, "simulation failed: execution reverted", flashbotsrpc.ErrSimulationFailed},
+		{http.StatusOK, "bundle not found", nil},
+		{http.StatusInternalServerError, "internal server error", nil},
+		{http.StatusBadRequest, "TX NOT FOUND", flashbotsrpc.ErrTxNotFound},
+		{http.StatusOK, "simulation error: execution reverted", flashbotsrpc.ErrSimulationFailed},
+		{http.StatusForbidden, "tx not found", flashbotsrpc.ErrUnauthorized},
+
+		// Messages which only mention a known failure
+		{http.StatusOK, "simulation failed: invalid signer nonce", flashbotsrpc.ErrSimulationFailed},
+		{http.StatusOK, "execution reverted: ECDSA: invalid signature", nil},
+		{http.StatusOK, "signer has insufficient funds", nil},
+		{http.StatusBadRequest, "replacement bundle expired", nil},
+		{http.StatusBadRequest, "permit expired", nil},
+		{http.StatusOK, "bundle tx not found in mempool", nil},
+		{http.StatusOK, "response too large for buffer", nil},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.kind, flashbotsrpc.RelayErrorKind(test.statusCode, test.message), test.message)
+	}
+}
+
+func TestRelayError(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	server := flashbotstest.NewNode()
+	defer server.Close()
+	server.SetFault("eth_cancelPrivateTransaction", flashbotstest.Fault{StatusCode: http.StatusBadRequest, Message: "tx not found"})
+	_, err = flashbotsrpc.NewFlashbotsRPC(server.URL).FlashbotsCancelPrivateTransaction(key, flashbotsrpc.FlashbotsCancelPrivateTransactionRequest{TxHash: "0x1234"})
+	require.ErrorIs(t, err, flashbotsrpc.ErrRelayErrorResponse)
+	require.ErrorIs(t, err, flashbotsrpc.ErrTxNotFound)
+	require.False(t, errors.Is(err, flashbotsrpc.ErrTxExpired))
+	require.Equal(t, "relay error response: tx not found", err.Error())
+
+	var relayErr *flashbotsrpc.RelayError
+	require.True(t, errors.As(err, &relayErr))
+	require.Equal(t, "eth_cancelPrivateTransaction", relayErr.Method)
+	require.Equal(t, server.URL, relayErr.Endpoint)
+	require.Equal(t, http.StatusBadRequest, relayErr.StatusCode)
+	require.JSONEq(t, `{"error":"tx not found"}`, string(relayErr.Body))
+
+	// JSON-RPC errors keep their code and data
+	server = flashbotstest.NewNode()
+	defer server.Close()
+	server.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{Body: `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"simulation failed","data":{"txHash":"0x5678"}}}`})
+	_, err = flashbotsrpc.NewFlashbotsRPC(server.URL).FlashbotsCallBundle(key, flashbotsrpc.FlashbotsCallBundleParam{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.ErrorIs(t, err, flashbotsrpc.ErrSimulationFailed)
+	require.True(t, errors.As(err, &relayErr))
+	require.Equal(t, -32000, relayErr.Code)
+	require.JSONEq(t, `{"txHash":"0x5678"}`, string(relayErr.Data))
+
+	results := flashbotsrpc.NewBuilderBroadcastRPC([]string{server.URL}).BroadcastCallBundle(key, flashbotsrpc.FlashbotsCallBundleParam{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.ErrorIs(t, results[0].Err, flashbotsrpc.ErrSimulationFailed)
+	require.True(t, errors.As(results[0].Err, &relayErr))
+	require.Equal(t, "eth_callBundle", relayErr.Method)
+	require.Equal(t, server.URL, relayErr.Endpoint)
+
+	// Batches answered with a single error
+	batch := []flashbotsrpc.BatchElem{{Method: "eth_callBundle"}, {Method: "eth_sendBundle"}}
+	err = flashbotsrpc.NewFlashbotsRPC(server.URL).BatchCallWithFlashbotsSignatureContext(context.Background(), flashbotsrpc.NewPrivateKeySigner(key), batch)
+	require.ErrorIs(t, err, flashbotsrpc.ErrSimulationFailed)
+	require.True(t, errors.As(err, &relayErr))
+	require.Equal(t, "eth_callBundle,eth_sendBundle", relayErr.Method)
+
+	// Errors of batch elements
+	server = flashbotstest.NewNode()
+	defer server.Close()
+	server.SetFault("eth_cancelPrivateTransaction", flashbotstest.Fault{Code: flashbotstest.CodeServerError, Message: "tx has already expired"})
+	batch = []flashbotsrpc.BatchElem{{Method: "eth_cancelPrivateTransaction"}}
+	require.NoError(t, flashbotsrpc.NewFlashbotsRPC(server.URL).BatchCallWithFlashbotsSignatureContext(context.Background(), flashbotsrpc.NewPrivateKeySigner(key), batch))
+	require.ErrorIs(t, batch[0].Error, flashbotsrpc.ErrTxExpired)
+	require.True(t, errors.As(batch[0].Error, &relayErr))
+	require.Equal(t, "eth_cancelPrivateTransaction", relayErr.Method)
+}
+
+func TestRpcErrorData(t *testing.T) {
+	server := flashbotstest.NewNode()
+	defer server.Close()
+	server.SetFault("eth_call", flashbotstest.Fault{Body: `{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":"0x08c379a0"}}`})
+
+	_, err := flashbotsrpc.NewFlashbotsRPC(server.URL).Call("eth_call")
+	var rpcErr flashbotsrpc.RpcError
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, 3, rpcErr.Code)
+	require.Equal(t, `"0x08c379a0"`, string(rpcErr.Data))
+}
@@ -42,14 +42,14 @@ func main() {
 		if result.Err != nil {
 			if errors.Is(result.Err, flashbotsrpc.ErrRelayErrorResponse) {
 				// ErrRelayErrorResponse means it's a standard Flashbots relay error response, so probably a user error, rather than JSON or network error
-				fmt.Println(result.Err.Error())
+				fmt.Println(result.URL, result.Err.Error())
 			} else {
-				fmt.Printf("error: %+v\n", result.Err)
+				fmt.Printf("%s error (HTTP %d): %+v\n", result.URL, result.StatusCode, result.Err)
 			}
-			return
+			continue
 		}
 
 		// Print result
-		fmt.Printf("%+v\n", result.BundleResponse)
+		fmt.Printf("%s (%s): %+v\n", result.URL, result.Latency, result.BundleResponse)
 	}
 }
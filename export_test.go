@@ -0,0 +1,47 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"time"
+)
+
+// Internals used by the tests of package flashbotsrpc_test
+var (
+	FlashbotsSignature = flashbotsSignature
+	RelayErrorKind     = relayErrorKind
+	MaxBodySnippet     = maxBodySnippet
+	ParseRetryAfter    = parseRetryAfter
+	FirstSuccess       = firstSuccess
+)
+
+// WithRateLimiterClock makes the limiter use a fake clock, and sleep if it isn't nil
+func WithRateLimiterClock(now func() time.Time, sleep func(ctx context.Context, d time.Duration) error) func(limiter *RateLimiter) {
+	return func(limiter *RateLimiter) {
+		limiter.now = now
+		if sleep != nil {
+			limiter.sleep = sleep
+		}
+	}
+}
+
+// EndpointTokens returns the tokens left in the endpoint bucket
+func (limiter *RateLimiter) EndpointTokens() float64 {
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+	return limiter.endpoint.tokens
+}
+
+// Endpoints returns the builders of the broadcaster
+func (rpc *BuilderBroadcastRPC) Endpoints() []BuilderEndpoint {
+	return rpc.endpoints
+}
+
+// Backoff returns the backoff before the given attempt
+func (policy RetryPolicy) Backoff(attempt int, retryAfter time.Duration) time.Duration {
+	return policy.backoff(attempt, retryAfter)
+}
+
+// IsIdempotent returns whether all methods may be retried
+func (policy RetryPolicy) IsIdempotent(methods ...string) bool {
+	return policy.isIdempotent(methods...)
+}
@@ -2,16 +2,15 @@ package flashbotsrpc
 
 import (
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"log"
 	"math/big"
 	"net/http"
 	"os"
-	"sync"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
@@ -22,8 +21,9 @@ import (
 
 // RpcError - ethereum error
 type RpcError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
 }
 
 func (err RpcError) Error() string {
@@ -51,7 +51,14 @@ type FlashbotsRPC struct {
 	log     logger
 	Debug   bool
 	Headers map[string]string // Additional headers to send with the request
-	Timeout time.Duration
+	Timeout time.Duration     // Timeout of each attempt, only used by New for the default http client
+	Retry   RetryPolicy       // Retries of failed requests, no retries by default
+
+	MaxResponseSize int64 // Larger responses fail with ErrResponseTooLarge, defaults to DefaultMaxResponseSize
+
+	RateLimiter *RateLimiter // (Optional) Limits the requests to the url, including retries
+
+	middleware []Middleware
 }
 
 // New create new rpc client with given url
@@ -79,7 +86,11 @@ func NewFlashbotsRPC(url string, options ...func(rpc *FlashbotsRPC)) *FlashbotsR
 }
 
 func (rpc *FlashbotsRPC) call(method string, target interface{}, params ...interface{}) error {
-	result, err := rpc.Call(method, params...)
+	return rpc.callContext(context.Background(), method, target, params...)
+}
+
+func (rpc *FlashbotsRPC) callContext(ctx context.Context, method string, target interface{}, params ...interface{}) error {
+	result, err := rpc.CallContext(ctx, method, params...)
 	if err != nil {
 		return err
 	}
@@ -98,6 +109,12 @@ func (rpc *FlashbotsRPC) URL() string {
 
 // Call returns raw response of method call
 func (rpc *FlashbotsRPC) Call(method string, params ...interface{}) (json.RawMessage, error) {
+	return rpc.CallContext(context.Background(), method, params...)
+}
+
+// CallContext is like Call but takes a context. The context is attached to the HTTP request, so cancelling it
+// or hitting its deadline aborts the request in flight.
+func (rpc *FlashbotsRPC) CallContext(ctx context.Context, method string, params ...interface{}) (json.RawMessage, error) {
 	request := rpcRequest{
 		ID:      1,
 		JSONRPC: "2.0",
@@ -110,26 +127,7 @@ func (rpc *FlashbotsRPC) Call(method string, params ...interface{}) (json.RawMes
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", rpc.url, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
-	for k, v := range rpc.Headers {
-		req.Header.Add(k, v)
-	}
-
-	response, err := rpc.client.Do(req)
-	if response != nil {
-		defer response.Body.Close()
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := io.ReadAll(response.Body)
+	data, statusCode, err := rpc.post(ctx, body, "", nil, method)
 	if err != nil {
 		return nil, err
 	}
@@ -138,20 +136,20 @@ func (rpc *FlashbotsRPC) Call(method string, params ...interface{}) (json.RawMes
 		rpc.log.Println(fmt.Sprintf("%s\nRequest: %s\nResponse: %s\n", method, body, data))
 	}
 
-	resp := new(rpcResponse)
-	if err := json.Unmarshal(data, resp); err != nil {
-		return nil, err
-	}
-
-	if resp.Error != nil {
-		return nil, *resp.Error
-	}
-
-	return resp.Result, nil
+	return httpResponse{method: method, endpoint: rpc.url, statusCode: statusCode, body: data}.result(false)
 }
 
 // CallWithFlashbotsSignature is like Call but also signs the request
 func (rpc *FlashbotsRPC) CallWithFlashbotsSignature(method string, privKey *ecdsa.PrivateKey, params ...interface{}) (json.RawMessage, error) {
+	return rpc.CallWithFlashbotsSignatureContext(context.Background(), method, NewPrivateKeySigner(privKey), params...)
+}
+
+// CallWithFlashbotsSignatureContext is like CallWithFlashbotsSignature but takes a context for cancellation and deadlines,
+// and a Signer instead of a private key.
+//
+// All other Flashbots methods also have a Context variant which takes a Signer, for keys that are not held in memory
+// (see NewKeystoreSigner and NewRemoteSigner).
+func (rpc *FlashbotsRPC) CallWithFlashbotsSignatureContext(ctx context.Context, method string, signer Signer, params ...interface{}) (json.RawMessage, error) {
 	request := rpcRequest{
 		ID:      1,
 		JSONRPC: "2.0",
@@ -164,65 +162,81 @@ func (rpc *FlashbotsRPC) CallWithFlashbotsSignature(method string, privKey *ecds
 		return nil, err
 	}
 
-	hashedBody := crypto.Keccak256Hash([]byte(body)).Hex()
-	sig, err := crypto.Sign(accounts.TextHash([]byte(hashedBody)), privKey)
+	signature, err := flashbotsSignature(ctx, body, signer)
 	if err != nil {
 		return nil, err
 	}
 
-	signature := crypto.PubkeyToAddress(privKey.PublicKey).Hex() + ":" + hexutil.Encode(sig)
-
-	req, err := http.NewRequest("POST", rpc.url, bytes.NewBuffer(body))
+	data, statusCode, err := rpc.post(ctx, body, signature, signer, method)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("X-Flashbots-Signature", signature)
-	for k, v := range rpc.Headers {
-		req.Header.Add(k, v)
+	if rpc.Debug {
+		rpc.log.Println(fmt.Sprintf("%s\nRequest: %s\nSignature: %s\nResponse: %s\n", method, body, signature, data))
 	}
 
-	response, err := rpc.client.Do(req)
-	if response != nil {
-		defer response.Body.Close()
-	}
-	if err != nil {
-		return nil, err
-	}
+	// On error, response looks like this instead of JSON-RPC: {"error":"block param must be a hex int"}
+	return httpResponse{method: method, endpoint: rpc.url, statusCode: statusCode, body: data}.result(true)
+}
+
+// post sends a JSON-RPC request body to the rpc url and returns the response body and HTTP status code.
+// If signature is not empty, it is sent in the X-Flashbots-Signature header, signer is its signer for middleware.
+// methods are the methods of the request, for the rate limiter and to decide whether failed attempts can be retried.
+func (rpc *FlashbotsRPC) post(ctx context.Context, body []byte, signature string, signer Signer, methods ...string) ([]byte, int, error) {
+	idempotent := rpc.Retry.isIdempotent(methods...)
+	for attempt := 1; ; attempt++ {
+		if rpc.RateLimiter != nil {
+			if err := rpc.RateLimiter.Wait(ctx, methods...); err != nil {
+				return nil, 0, err
+			}
+		}
 
-	data, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
+		data, statusCode, retryAfter, err := rpc.postOnce(ctx, body, signature, signer, strings.Join(methods, ","))
+		if ctx.Err() != nil || !rpc.Retry.shouldRetry(attempt, statusCode, err, idempotent) {
+			return data, statusCode, err
+		}
 
-	if rpc.Debug {
-		rpc.log.Println(fmt.Sprintf("%s\nRequest: %s\nSignature: %s\nResponse: %s\n", method, body, signature, data))
+		backoff := rpc.Retry.backoff(attempt, retryAfter)
+		if rpc.Debug {
+			rpc.log.Println(fmt.Sprintf("attempt %d failed (HTTP status code: %d, error: %v), retrying in %s", attempt, statusCode, err, backoff))
+		}
+		if err := sleepContext(ctx, backoff); err != nil {
+			return nil, 0, err
+		}
 	}
+}
 
-	// On error, response looks like this instead of JSON-RPC: {"error":"block param must be a hex int"}
-	errorResp := new(RelayErrorResponse)
-	if err := json.Unmarshal(data, errorResp); err == nil && errorResp.Error != "" {
-		// relay returned an error
-		return nil, fmt.Errorf("%w: %s", ErrRelayErrorResponse, errorResp.Error)
+// postOnce makes a single attempt of post through the middleware, and also returns the duration of the Retry-After
+// header of the response
+func (rpc *FlashbotsRPC) postOnce(ctx context.Context, body []byte, signature string, signer Signer, method string) ([]byte, int, time.Duration, error) {
+	req := newRoundTripRequest(ctx, method, rpc.url, body, signature, signer)
+	for k, v := range rpc.Headers {
+		req.Header.Add(k, v)
 	}
 
-	resp := new(rpcResponse)
-	if err := json.Unmarshal(data, resp); err != nil {
-		return nil, err
+	response, err := chainMiddleware(rpc.middleware, httpRoundTrip(rpc.client, rpc.MaxResponseSize))(ctx, req)
+	if response == nil {
+		return nil, 0, 0, err
 	}
 
-	if resp.ID != request.ID || resp.JSONRPC != request.JSONRPC {
-		// this means we got back JSON but not a valid JSONRPC response
-		return nil, fmt.Errorf("%w: invalid JSONRPC response (HTTP status code: %d)", ErrRelayErrorResponse, response.StatusCode)
+	retryAfter := parseRetryAfter(response.Header.Get("Retry-After"), time.Now())
+	if err != nil {
+		return nil, response.StatusCode, retryAfter, err
 	}
+	return response.Body, response.StatusCode, retryAfter, nil
+}
 
-	if resp.Error != nil {
-		return nil, fmt.Errorf("%w: %s", ErrRelayErrorResponse, (*resp).Error.Message)
+// flashbotsSignature returns the X-Flashbots-Signature header value for a request body: "<address>:<signature>",
+// where the signature is an EIP-191 signature over the hex-encoded keccak256 hash of the body.
+func flashbotsSignature(ctx context.Context, body []byte, signer Signer) (string, error) {
+	hashedBody := crypto.Keccak256Hash(body).Hex()
+	sig, err := signer.SignHashContext(ctx, accounts.TextHash([]byte(hashedBody)))
+	if err != nil {
+		return "", err
 	}
 
-	return resp.Result, nil
+	return signer.Address().Hex() + ":" + hexutil.Encode(sig), nil
 }
 
 // RawCall returns raw response of method call (Deprecated)
@@ -232,40 +246,65 @@ func (rpc *FlashbotsRPC) RawCall(method string, params ...interface{}) (json.Raw
 
 // Web3ClientVersion returns the current client version.
 func (rpc *FlashbotsRPC) Web3ClientVersion() (string, error) {
+	return rpc.Web3ClientVersionContext(context.Background())
+}
+
+// Web3ClientVersionContext is like Web3ClientVersion but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) Web3ClientVersionContext(ctx context.Context) (string, error) {
 	var clientVersion string
 
-	err := rpc.call("web3_clientVersion", &clientVersion)
+	err := rpc.callContext(ctx, "web3_clientVersion", &clientVersion)
 	return clientVersion, err
 }
 
 // Web3Sha3 returns Keccak-256 (not the standardized SHA3-256) of the given data.
 func (rpc *FlashbotsRPC) Web3Sha3(data []byte) (string, error) {
+	return rpc.Web3Sha3Context(context.Background(), data)
+}
+
+// Web3Sha3Context is like Web3Sha3 but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) Web3Sha3Context(ctx context.Context, data []byte) (string, error) {
 	var hash string
 
-	err := rpc.call("web3_sha3", &hash, fmt.Sprintf("0x%x", data))
+	err := rpc.callContext(ctx, "web3_sha3", &hash, fmt.Sprintf("0x%x", data))
 	return hash, err
 }
 
 // NetVersion returns the current network protocol version.
 func (rpc *FlashbotsRPC) NetVersion() (string, error) {
+	return rpc.NetVersionContext(context.Background())
+}
+
+// NetVersionContext is like NetVersion but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) NetVersionContext(ctx context.Context) (string, error) {
 	var version string
 
-	err := rpc.call("net_version", &version)
+	err := rpc.callContext(ctx, "net_version", &version)
 	return version, err
 }
 
 // NetListening returns true if client is actively listening for network connections.
 func (rpc *FlashbotsRPC) NetListening() (bool, error) {
+	return rpc.NetListeningContext(context.Background())
+}
+
+// NetListeningContext is like NetListening but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) NetListeningContext(ctx context.Context) (bool, error) {
 	var listening bool
 
-	err := rpc.call("net_listening", &listening)
+	err := rpc.callContext(ctx, "net_listening", &listening)
 	return listening, err
 }
 
 // NetPeerCount returns number of peers currently connected to the client.
 func (rpc *FlashbotsRPC) NetPeerCount() (int, error) {
+	return rpc.NetPeerCountContext(context.Background())
+}
+
+// NetPeerCountContext is like NetPeerCount but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) NetPeerCountContext(ctx context.Context) (int, error) {
 	var response string
-	if err := rpc.call("net_peerCount", &response); err != nil {
+	if err := rpc.callContext(ctx, "net_peerCount", &response); err != nil {
 		return 0, err
 	}
 
@@ -274,15 +313,25 @@ func (rpc *FlashbotsRPC) NetPeerCount() (int, error) {
 
 // EthProtocolVersion returns the current ethereum protocol version.
 func (rpc *FlashbotsRPC) EthProtocolVersion() (string, error) {
+	return rpc.EthProtocolVersionContext(context.Background())
+}
+
+// EthProtocolVersionContext is like EthProtocolVersion but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthProtocolVersionContext(ctx context.Context) (string, error) {
 	var protocolVersion string
 
-	err := rpc.call("eth_protocolVersion", &protocolVersion)
+	err := rpc.callContext(ctx, "eth_protocolVersion", &protocolVersion)
 	return protocolVersion, err
 }
 
 // EthSyncing returns an object with data about the sync status or false.
 func (rpc *FlashbotsRPC) EthSyncing() (*Syncing, error) {
-	result, err := rpc.RawCall("eth_syncing")
+	return rpc.EthSyncingContext(context.Background())
+}
+
+// EthSyncingContext is like EthSyncing but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthSyncingContext(ctx context.Context) (*Syncing, error) {
+	result, err := rpc.CallContext(ctx, "eth_syncing")
 	if err != nil {
 		return nil, err
 	}
@@ -296,25 +345,40 @@ func (rpc *FlashbotsRPC) EthSyncing() (*Syncing, error) {
 
 // EthCoinbase returns the client coinbase address
 func (rpc *FlashbotsRPC) EthCoinbase() (string, error) {
+	return rpc.EthCoinbaseContext(context.Background())
+}
+
+// EthCoinbaseContext is like EthCoinbase but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthCoinbaseContext(ctx context.Context) (string, error) {
 	var address string
 
-	err := rpc.call("eth_coinbase", &address)
+	err := rpc.callContext(ctx, "eth_coinbase", &address)
 	return address, err
 }
 
 // EthMining returns true if client is actively mining new blocks.
 func (rpc *FlashbotsRPC) EthMining() (bool, error) {
+	return rpc.EthMiningContext(context.Background())
+}
+
+// EthMiningContext is like EthMining but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthMiningContext(ctx context.Context) (bool, error) {
 	var mining bool
 
-	err := rpc.call("eth_mining", &mining)
+	err := rpc.callContext(ctx, "eth_mining", &mining)
 	return mining, err
 }
 
 // EthHashrate returns the number of hashes per second that the node is mining with.
 func (rpc *FlashbotsRPC) EthHashrate() (int, error) {
+	return rpc.EthHashrateContext(context.Background())
+}
+
+// EthHashrateContext is like EthHashrate but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthHashrateContext(ctx context.Context) (int, error) {
 	var response string
 
-	if err := rpc.call("eth_hashrate", &response); err != nil {
+	if err := rpc.callContext(ctx, "eth_hashrate", &response); err != nil {
 		return 0, err
 	}
 
@@ -323,8 +387,13 @@ func (rpc *FlashbotsRPC) EthHashrate() (int, error) {
 
 // EthGasPrice returns the current price per gas in wei.
 func (rpc *FlashbotsRPC) EthGasPrice() (big.Int, error) {
+	return rpc.EthGasPriceContext(context.Background())
+}
+
+// EthGasPriceContext is like EthGasPrice but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGasPriceContext(ctx context.Context) (big.Int, error) {
 	var response string
-	if err := rpc.call("eth_gasPrice", &response); err != nil {
+	if err := rpc.callContext(ctx, "eth_gasPrice", &response); err != nil {
 		return big.Int{}, err
 	}
 
@@ -333,16 +402,26 @@ func (rpc *FlashbotsRPC) EthGasPrice() (big.Int, error) {
 
 // EthAccounts returns a list of addresses owned by client.
 func (rpc *FlashbotsRPC) EthAccounts() ([]string, error) {
+	return rpc.EthAccountsContext(context.Background())
+}
+
+// EthAccountsContext is like EthAccounts but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthAccountsContext(ctx context.Context) ([]string, error) {
 	accounts := []string{}
 
-	err := rpc.call("eth_accounts", &accounts)
+	err := rpc.callContext(ctx, "eth_accounts", &accounts)
 	return accounts, err
 }
 
 // EthBlockNumber returns the number of most recent block.
 func (rpc *FlashbotsRPC) EthBlockNumber() (int, error) {
+	return rpc.EthBlockNumberContext(context.Background())
+}
+
+// EthBlockNumberContext is like EthBlockNumber but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthBlockNumberContext(ctx context.Context) (int, error) {
 	var response string
-	if err := rpc.call("eth_blockNumber", &response); err != nil {
+	if err := rpc.callContext(ctx, "eth_blockNumber", &response); err != nil {
 		return 0, err
 	}
 
@@ -351,8 +430,13 @@ func (rpc *FlashbotsRPC) EthBlockNumber() (int, error) {
 
 // EthGetBalance returns the balance of the account of given address in wei.
 func (rpc *FlashbotsRPC) EthGetBalance(address, block string) (big.Int, error) {
+	return rpc.EthGetBalanceContext(context.Background(), address, block)
+}
+
+// EthGetBalanceContext is like EthGetBalance but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetBalanceContext(ctx context.Context, address, block string) (big.Int, error) {
 	var response string
-	if err := rpc.call("eth_getBalance", &response, address, block); err != nil {
+	if err := rpc.callContext(ctx, "eth_getBalance", &response, address, block); err != nil {
 		return big.Int{}, err
 	}
 
@@ -361,17 +445,27 @@ func (rpc *FlashbotsRPC) EthGetBalance(address, block string) (big.Int, error) {
 
 // EthGetStorageAt returns the value from a storage position at a given address.
 func (rpc *FlashbotsRPC) EthGetStorageAt(data string, position int, tag string) (string, error) {
+	return rpc.EthGetStorageAtContext(context.Background(), data, position, tag)
+}
+
+// EthGetStorageAtContext is like EthGetStorageAt but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetStorageAtContext(ctx context.Context, data string, position int, tag string) (string, error) {
 	var result string
 
-	err := rpc.call("eth_getStorageAt", &result, data, IntToHex(position), tag)
+	err := rpc.callContext(ctx, "eth_getStorageAt", &result, data, IntToHex(position), tag)
 	return result, err
 }
 
 // EthGetTransactionCount returns the number of transactions sent from an address.
 func (rpc *FlashbotsRPC) EthGetTransactionCount(address, block string) (int, error) {
+	return rpc.EthGetTransactionCountContext(context.Background(), address, block)
+}
+
+// EthGetTransactionCountContext is like EthGetTransactionCount but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetTransactionCountContext(ctx context.Context, address, block string) (int, error) {
 	var response string
 
-	if err := rpc.call("eth_getTransactionCount", &response, address, block); err != nil {
+	if err := rpc.callContext(ctx, "eth_getTransactionCount", &response, address, block); err != nil {
 		return 0, err
 	}
 
@@ -380,9 +474,14 @@ func (rpc *FlashbotsRPC) EthGetTransactionCount(address, block string) (int, err
 
 // EthGetBlockTransactionCountByHash returns the number of transactions in a block from a block matching the given block hash.
 func (rpc *FlashbotsRPC) EthGetBlockTransactionCountByHash(hash string) (int, error) {
+	return rpc.EthGetBlockTransactionCountByHashContext(context.Background(), hash)
+}
+
+// EthGetBlockTransactionCountByHashContext is like EthGetBlockTransactionCountByHash but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetBlockTransactionCountByHashContext(ctx context.Context, hash string) (int, error) {
 	var response string
 
-	if err := rpc.call("eth_getBlockTransactionCountByHash", &response, hash); err != nil {
+	if err := rpc.callContext(ctx, "eth_getBlockTransactionCountByHash", &response, hash); err != nil {
 		return 0, err
 	}
 
@@ -391,9 +490,14 @@ func (rpc *FlashbotsRPC) EthGetBlockTransactionCountByHash(hash string) (int, er
 
 // EthGetBlockTransactionCountByNumber returns the number of transactions in a block from a block matching the given block
 func (rpc *FlashbotsRPC) EthGetBlockTransactionCountByNumber(number int) (int, error) {
+	return rpc.EthGetBlockTransactionCountByNumberContext(context.Background(), number)
+}
+
+// EthGetBlockTransactionCountByNumberContext is like EthGetBlockTransactionCountByNumber but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetBlockTransactionCountByNumberContext(ctx context.Context, number int) (int, error) {
 	var response string
 
-	if err := rpc.call("eth_getBlockTransactionCountByNumber", &response, IntToHex(number)); err != nil {
+	if err := rpc.callContext(ctx, "eth_getBlockTransactionCountByNumber", &response, IntToHex(number)); err != nil {
 		return 0, err
 	}
 
@@ -402,9 +506,14 @@ func (rpc *FlashbotsRPC) EthGetBlockTransactionCountByNumber(number int) (int, e
 
 // EthGetUncleCountByBlockHash returns the number of uncles in a block from a block matching the given block hash.
 func (rpc *FlashbotsRPC) EthGetUncleCountByBlockHash(hash string) (int, error) {
+	return rpc.EthGetUncleCountByBlockHashContext(context.Background(), hash)
+}
+
+// EthGetUncleCountByBlockHashContext is like EthGetUncleCountByBlockHash but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetUncleCountByBlockHashContext(ctx context.Context, hash string) (int, error) {
 	var response string
 
-	if err := rpc.call("eth_getUncleCountByBlockHash", &response, hash); err != nil {
+	if err := rpc.callContext(ctx, "eth_getUncleCountByBlockHash", &response, hash); err != nil {
 		return 0, err
 	}
 
@@ -413,9 +522,14 @@ func (rpc *FlashbotsRPC) EthGetUncleCountByBlockHash(hash string) (int, error) {
 
 // EthGetUncleCountByBlockNumber returns the number of uncles in a block from a block matching the given block number.
 func (rpc *FlashbotsRPC) EthGetUncleCountByBlockNumber(number int) (int, error) {
+	return rpc.EthGetUncleCountByBlockNumberContext(context.Background(), number)
+}
+
+// EthGetUncleCountByBlockNumberContext is like EthGetUncleCountByBlockNumber but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetUncleCountByBlockNumberContext(ctx context.Context, number int) (int, error) {
 	var response string
 
-	if err := rpc.call("eth_getUncleCountByBlockNumber", &response, IntToHex(number)); err != nil {
+	if err := rpc.callContext(ctx, "eth_getUncleCountByBlockNumber", &response, IntToHex(number)); err != nil {
 		return 0, err
 	}
 
@@ -424,50 +538,80 @@ func (rpc *FlashbotsRPC) EthGetUncleCountByBlockNumber(number int) (int, error)
 
 // EthGetCode returns code at a given address.
 func (rpc *FlashbotsRPC) EthGetCode(address, block string) (string, error) {
+	return rpc.EthGetCodeContext(context.Background(), address, block)
+}
+
+// EthGetCodeContext is like EthGetCode but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetCodeContext(ctx context.Context, address, block string) (string, error) {
 	var code string
 
-	err := rpc.call("eth_getCode", &code, address, block)
+	err := rpc.callContext(ctx, "eth_getCode", &code, address, block)
 	return code, err
 }
 
 // EthSign signs data with a given address.
 // Calculates an Ethereum specific signature with: sign(keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)))
 func (rpc *FlashbotsRPC) EthSign(address, data string) (string, error) {
+	return rpc.EthSignContext(context.Background(), address, data)
+}
+
+// EthSignContext is like EthSign but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthSignContext(ctx context.Context, address, data string) (string, error) {
 	var signature string
 
-	err := rpc.call("eth_sign", &signature, address, data)
+	err := rpc.callContext(ctx, "eth_sign", &signature, address, data)
 	return signature, err
 }
 
 // EthSendTransaction creates new message call transaction or a contract creation, if the data field contains code.
 func (rpc *FlashbotsRPC) EthSendTransaction(transaction T) (string, error) {
+	return rpc.EthSendTransactionContext(context.Background(), transaction)
+}
+
+// EthSendTransactionContext is like EthSendTransaction but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthSendTransactionContext(ctx context.Context, transaction T) (string, error) {
 	var hash string
 
-	err := rpc.call("eth_sendTransaction", &hash, transaction)
+	err := rpc.callContext(ctx, "eth_sendTransaction", &hash, transaction)
 	return hash, err
 }
 
 // EthSendRawTransaction creates new message call transaction or a contract creation for signed transactions.
 func (rpc *FlashbotsRPC) EthSendRawTransaction(data string) (string, error) {
+	return rpc.EthSendRawTransactionContext(context.Background(), data)
+}
+
+// EthSendRawTransactionContext is like EthSendRawTransaction but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthSendRawTransactionContext(ctx context.Context, data string) (string, error) {
 	var hash string
 
-	err := rpc.call("eth_sendRawTransaction", &hash, data)
+	err := rpc.callContext(ctx, "eth_sendRawTransaction", &hash, data)
 	return hash, err
 }
 
 // EthCall executes a new message call immediately without creating a transaction on the block chain.
 func (rpc *FlashbotsRPC) EthCall(transaction T, tag string) (string, error) {
+	return rpc.EthCallContext(context.Background(), transaction, tag)
+}
+
+// EthCallContext is like EthCall but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthCallContext(ctx context.Context, transaction T, tag string) (string, error) {
 	var data string
 
-	err := rpc.call("eth_call", &data, transaction, tag)
+	err := rpc.callContext(ctx, "eth_call", &data, transaction, tag)
 	return data, err
 }
 
 // EthEstimateGas makes a call or transaction, which won't be added to the blockchain and returns the used gas, which can be used for estimating the used gas.
 func (rpc *FlashbotsRPC) EthEstimateGas(transaction T) (int, error) {
+	return rpc.EthEstimateGasContext(context.Background(), transaction)
+}
+
+// EthEstimateGasContext is like EthEstimateGas but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthEstimateGasContext(ctx context.Context, transaction T) (int, error) {
 	var response string
 
-	err := rpc.call("eth_estimateGas", &response, transaction)
+	err := rpc.callContext(ctx, "eth_estimateGas", &response, transaction)
 	if err != nil {
 		return 0, err
 	}
@@ -475,8 +619,8 @@ func (rpc *FlashbotsRPC) EthEstimateGas(transaction T) (int, error) {
 	return ParseInt(response)
 }
 
-func (rpc *FlashbotsRPC) getBlock(method string, withTransactions bool, params ...interface{}) (*Block, error) {
-	result, err := rpc.RawCall(method, params...)
+func (rpc *FlashbotsRPC) getBlock(ctx context.Context, method string, withTransactions bool, params ...interface{}) (*Block, error) {
+	result, err := rpc.CallContext(ctx, method, params...)
 	if err != nil {
 		return nil, err
 	}
@@ -502,42 +646,72 @@ func (rpc *FlashbotsRPC) getBlock(method string, withTransactions bool, params .
 
 // EthGetBlockByHash returns information about a block by hash.
 func (rpc *FlashbotsRPC) EthGetBlockByHash(hash string, withTransactions bool) (*Block, error) {
-	return rpc.getBlock("eth_getBlockByHash", withTransactions, hash, withTransactions)
+	return rpc.EthGetBlockByHashContext(context.Background(), hash, withTransactions)
+}
+
+// EthGetBlockByHashContext is like EthGetBlockByHash but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetBlockByHashContext(ctx context.Context, hash string, withTransactions bool) (*Block, error) {
+	return rpc.getBlock(ctx, "eth_getBlockByHash", withTransactions, hash, withTransactions)
 }
 
 // EthGetBlockByNumber returns information about a block by block number.
 func (rpc *FlashbotsRPC) EthGetBlockByNumber(number int, withTransactions bool) (*Block, error) {
-	return rpc.getBlock("eth_getBlockByNumber", withTransactions, IntToHex(number), withTransactions)
+	return rpc.EthGetBlockByNumberContext(context.Background(), number, withTransactions)
 }
 
-func (rpc *FlashbotsRPC) getTransaction(method string, params ...interface{}) (*Transaction, error) {
+// EthGetBlockByNumberContext is like EthGetBlockByNumber but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetBlockByNumberContext(ctx context.Context, number int, withTransactions bool) (*Block, error) {
+	return rpc.getBlock(ctx, "eth_getBlockByNumber", withTransactions, IntToHex(number), withTransactions)
+}
+
+func (rpc *FlashbotsRPC) getTransaction(ctx context.Context, method string, params ...interface{}) (*Transaction, error) {
 	transaction := new(Transaction)
 
-	err := rpc.call(method, transaction, params...)
+	err := rpc.callContext(ctx, method, transaction, params...)
 	return transaction, err
 }
 
 // EthGetTransactionByHash returns the information about a transaction requested by transaction hash.
 func (rpc *FlashbotsRPC) EthGetTransactionByHash(hash string) (*Transaction, error) {
-	return rpc.getTransaction("eth_getTransactionByHash", hash)
+	return rpc.EthGetTransactionByHashContext(context.Background(), hash)
+}
+
+// EthGetTransactionByHashContext is like EthGetTransactionByHash but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetTransactionByHashContext(ctx context.Context, hash string) (*Transaction, error) {
+	return rpc.getTransaction(ctx, "eth_getTransactionByHash", hash)
 }
 
 // EthGetTransactionByBlockHashAndIndex returns information about a transaction by block hash and transaction index position.
 func (rpc *FlashbotsRPC) EthGetTransactionByBlockHashAndIndex(blockHash string, transactionIndex int) (*Transaction, error) {
-	return rpc.getTransaction("eth_getTransactionByBlockHashAndIndex", blockHash, IntToHex(transactionIndex))
+	return rpc.EthGetTransactionByBlockHashAndIndexContext(context.Background(), blockHash, transactionIndex)
+}
+
+// EthGetTransactionByBlockHashAndIndexContext is like EthGetTransactionByBlockHashAndIndex but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetTransactionByBlockHashAndIndexContext(ctx context.Context, blockHash string, transactionIndex int) (*Transaction, error) {
+	return rpc.getTransaction(ctx, "eth_getTransactionByBlockHashAndIndex", blockHash, IntToHex(transactionIndex))
 }
 
 // EthGetTransactionByBlockNumberAndIndex returns information about a transaction by block number and transaction index position.
 func (rpc *FlashbotsRPC) EthGetTransactionByBlockNumberAndIndex(blockNumber, transactionIndex int) (*Transaction, error) {
-	return rpc.getTransaction("eth_getTransactionByBlockNumberAndIndex", IntToHex(blockNumber), IntToHex(transactionIndex))
+	return rpc.EthGetTransactionByBlockNumberAndIndexContext(context.Background(), blockNumber, transactionIndex)
+}
+
+// EthGetTransactionByBlockNumberAndIndexContext is like EthGetTransactionByBlockNumberAndIndex but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetTransactionByBlockNumberAndIndexContext(ctx context.Context, blockNumber, transactionIndex int) (*Transaction, error) {
+	return rpc.getTransaction(ctx, "eth_getTransactionByBlockNumberAndIndex", IntToHex(blockNumber), IntToHex(transactionIndex))
 }
 
 // EthGetTransactionReceipt returns the receipt of a transaction by transaction hash.
 // Note That the receipt is not available for pending transactions.
 func (rpc *FlashbotsRPC) EthGetTransactionReceipt(hash string) (*TransactionReceipt, error) {
+	return rpc.EthGetTransactionReceiptContext(context.Background(), hash)
+}
+
+// EthGetTransactionReceiptContext is like EthGetTransactionReceipt but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetTransactionReceiptContext(ctx context.Context, hash string) (*TransactionReceipt, error) {
 	transactionReceipt := new(TransactionReceipt)
 
-	err := rpc.call("eth_getTransactionReceipt", transactionReceipt, hash)
+	err := rpc.callContext(ctx, "eth_getTransactionReceipt", transactionReceipt, hash)
 	if err != nil {
 		return nil, err
 	}
@@ -547,60 +721,100 @@ func (rpc *FlashbotsRPC) EthGetTransactionReceipt(hash string) (*TransactionRece
 
 // EthGetCompilers returns a list of available compilers in the client.
 func (rpc *FlashbotsRPC) EthGetCompilers() ([]string, error) {
+	return rpc.EthGetCompilersContext(context.Background())
+}
+
+// EthGetCompilersContext is like EthGetCompilers but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetCompilersContext(ctx context.Context) ([]string, error) {
 	compilers := []string{}
 
-	err := rpc.call("eth_getCompilers", &compilers)
+	err := rpc.callContext(ctx, "eth_getCompilers", &compilers)
 	return compilers, err
 }
 
 // EthNewFilter creates a new filter object.
 func (rpc *FlashbotsRPC) EthNewFilter(params FilterParams) (string, error) {
+	return rpc.EthNewFilterContext(context.Background(), params)
+}
+
+// EthNewFilterContext is like EthNewFilter but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthNewFilterContext(ctx context.Context, params FilterParams) (string, error) {
 	var filterID string
-	err := rpc.call("eth_newFilter", &filterID, params)
+	err := rpc.callContext(ctx, "eth_newFilter", &filterID, params)
 	return filterID, err
 }
 
 // EthNewBlockFilter creates a filter in the node, to notify when a new block arrives.
 // To check if the state has changed, call EthGetFilterChanges.
 func (rpc *FlashbotsRPC) EthNewBlockFilter() (string, error) {
+	return rpc.EthNewBlockFilterContext(context.Background())
+}
+
+// EthNewBlockFilterContext is like EthNewBlockFilter but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthNewBlockFilterContext(ctx context.Context) (string, error) {
 	var filterID string
-	err := rpc.call("eth_newBlockFilter", &filterID)
+	err := rpc.callContext(ctx, "eth_newBlockFilter", &filterID)
 	return filterID, err
 }
 
 // EthNewPendingTransactionFilter creates a filter in the node, to notify when new pending transactions arrive.
 // To check if the state has changed, call EthGetFilterChanges.
 func (rpc *FlashbotsRPC) EthNewPendingTransactionFilter() (string, error) {
+	return rpc.EthNewPendingTransactionFilterContext(context.Background())
+}
+
+// EthNewPendingTransactionFilterContext is like EthNewPendingTransactionFilter but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthNewPendingTransactionFilterContext(ctx context.Context) (string, error) {
 	var filterID string
-	err := rpc.call("eth_newPendingTransactionFilter", &filterID)
+	err := rpc.callContext(ctx, "eth_newPendingTransactionFilter", &filterID)
 	return filterID, err
 }
 
 // EthUninstallFilter uninstalls a filter with given id.
 func (rpc *FlashbotsRPC) EthUninstallFilter(filterID string) (bool, error) {
+	return rpc.EthUninstallFilterContext(context.Background(), filterID)
+}
+
+// EthUninstallFilterContext is like EthUninstallFilter but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthUninstallFilterContext(ctx context.Context, filterID string) (bool, error) {
 	var res bool
-	err := rpc.call("eth_uninstallFilter", &res, filterID)
+	err := rpc.callContext(ctx, "eth_uninstallFilter", &res, filterID)
 	return res, err
 }
 
 // EthGetFilterChanges polling method for a filter, which returns an array of logs which occurred since last poll.
 func (rpc *FlashbotsRPC) EthGetFilterChanges(filterID string) ([]Log, error) {
+	return rpc.EthGetFilterChangesContext(context.Background(), filterID)
+}
+
+// EthGetFilterChangesContext is like EthGetFilterChanges but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetFilterChangesContext(ctx context.Context, filterID string) ([]Log, error) {
 	var logs = []Log{}
-	err := rpc.call("eth_getFilterChanges", &logs, filterID)
+	err := rpc.callContext(ctx, "eth_getFilterChanges", &logs, filterID)
 	return logs, err
 }
 
 // EthGetFilterLogs returns an array of all logs matching filter with given id.
 func (rpc *FlashbotsRPC) EthGetFilterLogs(filterID string) ([]Log, error) {
+	return rpc.EthGetFilterLogsContext(context.Background(), filterID)
+}
+
+// EthGetFilterLogsContext is like EthGetFilterLogs but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetFilterLogsContext(ctx context.Context, filterID string) ([]Log, error) {
 	var logs = []Log{}
-	err := rpc.call("eth_getFilterLogs", &logs, filterID)
+	err := rpc.callContext(ctx, "eth_getFilterLogs", &logs, filterID)
 	return logs, err
 }
 
 // EthGetLogs returns an array of all logs matching a given filter object.
 func (rpc *FlashbotsRPC) EthGetLogs(params FilterParams) ([]Log, error) {
+	return rpc.EthGetLogsContext(context.Background(), params)
+}
+
+// EthGetLogsContext is like EthGetLogs but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) EthGetLogsContext(ctx context.Context, params FilterParams) ([]Log, error) {
 	var logs = []Log{}
-	err := rpc.call("eth_getLogs", &logs, params)
+	err := rpc.callContext(ctx, "eth_getLogs", &logs, params)
 	return logs, err
 }
 
@@ -616,7 +830,12 @@ func Eth1() *big.Int {
 
 // https://docs.flashbots.net/flashbots-auction/searchers/advanced/rpc-endpoint#flashbots_getuserstats
 func (rpc *FlashbotsRPC) FlashbotsGetUserStats(privKey *ecdsa.PrivateKey, blockNumber uint64) (res FlashbotsUserStats, err error) {
-	rawMsg, err := rpc.CallWithFlashbotsSignature("flashbots_getUserStats", privKey, fmt.Sprintf("0x%x", blockNumber))
+	return rpc.FlashbotsGetUserStatsContext(context.Background(), NewPrivateKeySigner(privKey), blockNumber)
+}
+
+// FlashbotsGetUserStatsContext is like FlashbotsGetUserStats but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsGetUserStatsContext(ctx context.Context, signer Signer, blockNumber uint64) (res FlashbotsUserStats, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "flashbots_getUserStats", signer, fmt.Sprintf("0x%x", blockNumber))
 	if err != nil {
 		return res, err
 	}
@@ -626,7 +845,12 @@ func (rpc *FlashbotsRPC) FlashbotsGetUserStats(privKey *ecdsa.PrivateKey, blockN
 
 // https://docs.flashbots.net/flashbots-auction/searchers/advanced/rpc-endpoint#eth_callbundle
 func (rpc *FlashbotsRPC) FlashbotsCallBundle(privKey *ecdsa.PrivateKey, param FlashbotsCallBundleParam) (res FlashbotsCallBundleResponse, err error) {
-	rawMsg, err := rpc.CallWithFlashbotsSignature("eth_callBundle", privKey, param)
+	return rpc.FlashbotsCallBundleContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// FlashbotsCallBundleContext is like FlashbotsCallBundle but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsCallBundleContext(ctx context.Context, signer Signer, param FlashbotsCallBundleParam) (res FlashbotsCallBundleResponse, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "eth_callBundle", signer, param)
 	if err != nil {
 		return res, err
 	}
@@ -636,7 +860,12 @@ func (rpc *FlashbotsRPC) FlashbotsCallBundle(privKey *ecdsa.PrivateKey, param Fl
 
 // https://docs.flashbots.net/flashbots-auction/searchers/advanced/rpc-endpoint/#eth_sendbundle
 func (rpc *FlashbotsRPC) FlashbotsSendBundle(privKey *ecdsa.PrivateKey, param FlashbotsSendBundleRequest) (res FlashbotsSendBundleResponse, err error) {
-	rawMsg, err := rpc.CallWithFlashbotsSignature("eth_sendBundle", privKey, param)
+	return rpc.FlashbotsSendBundleContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// FlashbotsSendBundleContext is like FlashbotsSendBundle but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsSendBundleContext(ctx context.Context, signer Signer, param FlashbotsSendBundleRequest) (res FlashbotsSendBundleResponse, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "eth_sendBundle", signer, param)
 	if err != nil {
 		return res, err
 	}
@@ -644,8 +873,27 @@ func (rpc *FlashbotsRPC) FlashbotsSendBundle(privKey *ecdsa.PrivateKey, param Fl
 	return res, err
 }
 
+// Cancel a bundle which was sent with a ReplacementUUID. All bundles with this UUID are cancelled, but a bundle that was already
+// sent to builders might still be included.
+//
+// https://docs.flashbots.net/flashbots-auction/advanced/rpc-endpoint#eth_cancelbundle
+func (rpc *FlashbotsRPC) FlashbotsCancelBundle(privKey *ecdsa.PrivateKey, param FlashbotsCancelBundleRequest) error {
+	return rpc.FlashbotsCancelBundleContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// FlashbotsCancelBundleContext is like FlashbotsCancelBundle but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsCancelBundleContext(ctx context.Context, signer Signer, param FlashbotsCancelBundleRequest) error {
+	_, err := rpc.CallWithFlashbotsSignatureContext(ctx, "eth_cancelBundle", signer, param)
+	return err
+}
+
 func (rpc *FlashbotsRPC) FlashbotsGetBundleStats(privKey *ecdsa.PrivateKey, param FlashbotsGetBundleStatsParam) (res FlashbotsGetBundleStatsResponse, err error) {
-	rawMsg, err := rpc.CallWithFlashbotsSignature("flashbots_getBundleStats", privKey, param)
+	return rpc.FlashbotsGetBundleStatsContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// FlashbotsGetBundleStatsContext is like FlashbotsGetBundleStats but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsGetBundleStatsContext(ctx context.Context, signer Signer, param FlashbotsGetBundleStatsParam) (res FlashbotsGetBundleStatsResponse, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "flashbots_getBundleStats", signer, param)
 	if err != nil {
 		return res, err
 	}
@@ -654,7 +902,12 @@ func (rpc *FlashbotsRPC) FlashbotsGetBundleStats(privKey *ecdsa.PrivateKey, para
 }
 
 func (rpc *FlashbotsRPC) FlashbotsGetBundleStatsV2(privKey *ecdsa.PrivateKey, param FlashbotsGetBundleStatsParam) (res FlashbotsGetBundleStatsResponseV2, err error) {
-	rawMsg, err := rpc.CallWithFlashbotsSignature("flashbots_getBundleStatsV2", privKey, param)
+	return rpc.FlashbotsGetBundleStatsV2Context(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// FlashbotsGetBundleStatsV2Context is like FlashbotsGetBundleStatsV2 but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsGetBundleStatsV2Context(ctx context.Context, signer Signer, param FlashbotsGetBundleStatsParam) (res FlashbotsGetBundleStatsResponseV2, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "flashbots_getBundleStatsV2", signer, param)
 	if err != nil {
 		return res, err
 	}
@@ -664,6 +917,11 @@ func (rpc *FlashbotsRPC) FlashbotsGetBundleStatsV2(privKey *ecdsa.PrivateKey, pa
 
 // Simulate a full Ethereum block. numTx is the maximum number of tx to include, used for troubleshooting (default: 0 - all transactions)
 func (rpc *FlashbotsRPC) FlashbotsSimulateBlock(privKey *ecdsa.PrivateKey, block *types.Block, maxTx int) (res FlashbotsCallBundleResponse, err error) {
+	return rpc.FlashbotsSimulateBlockContext(context.Background(), NewPrivateKeySigner(privKey), block, maxTx)
+}
+
+// FlashbotsSimulateBlockContext is like FlashbotsSimulateBlock but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsSimulateBlockContext(ctx context.Context, signer Signer, block *types.Block, maxTx int) (res FlashbotsCallBundleResponse, err error) {
 	if rpc.Debug {
 		fmt.Printf("Simulating block %s 0x%x %s \t %d tx \t timestamp: %d\n", block.Number(), block.Number(), block.Header().Hash(), len(block.Transactions()), block.Header().Time)
 	}
@@ -720,13 +978,18 @@ func (rpc *FlashbotsRPC) FlashbotsSimulateBlock(privKey *ecdsa.PrivateKey, block
 		BaseFee:          block.BaseFee().Uint64(),
 	}
 
-	res, err = rpc.FlashbotsCallBundle(privKey, params)
+	res, err = rpc.FlashbotsCallBundleContext(ctx, signer, params)
 	return res, err
 }
 
 // Sends a rawTx to the Flashbots relay. It will be sent to miners as bundle for 25 blocks, after which the transaction is failed.
 func (rpc *FlashbotsRPC) FlashbotsSendPrivateTransaction(privKey *ecdsa.PrivateKey, param FlashbotsSendPrivateTransactionRequest) (txHash string, err error) {
-	rawMsg, err := rpc.CallWithFlashbotsSignature("eth_sendPrivateTransaction", privKey, param)
+	return rpc.FlashbotsSendPrivateTransactionContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// FlashbotsSendPrivateTransactionContext is like FlashbotsSendPrivateTransaction but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsSendPrivateTransactionContext(ctx context.Context, signer Signer, param FlashbotsSendPrivateTransactionRequest) (txHash string, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "eth_sendPrivateTransaction", signer, param)
 	if err != nil {
 		return "", err
 	}
@@ -738,177 +1001,52 @@ func (rpc *FlashbotsRPC) FlashbotsSendPrivateTransaction(privKey *ecdsa.PrivateK
 // that the transaction is actually cancelled, only that it won't be sent to miners anymore. A transaction that was already sent to miners might still
 // be included in the next block.
 //
-// Possible errors: 'tx not found', 'tx was already cancelled', 'tx has already expired'
+// Possible errors: ErrTxNotFound, ErrTxAlreadyCancelled and ErrTxExpired (see RelayError)
 func (rpc *FlashbotsRPC) FlashbotsCancelPrivateTransaction(privKey *ecdsa.PrivateKey, param FlashbotsCancelPrivateTransactionRequest) (cancelled bool, err error) {
-	rawMsg, err := rpc.CallWithFlashbotsSignature("eth_cancelPrivateTransaction", privKey, param)
+	return rpc.FlashbotsCancelPrivateTransactionContext(context.Background(), NewPrivateKeySigner(privKey), param)
+}
+
+// FlashbotsCancelPrivateTransactionContext is like FlashbotsCancelPrivateTransaction but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) FlashbotsCancelPrivateTransactionContext(ctx context.Context, signer Signer, param FlashbotsCancelPrivateTransactionRequest) (cancelled bool, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "eth_cancelPrivateTransaction", signer, param)
 	if err != nil {
-		// possible todo: return specific errors for the 3 possible relay-internal error cases
 		return false, err
 	}
 	err = json.Unmarshal(rawMsg, &cancelled)
 	return cancelled, err
 }
 
-type BuilderBroadcastRPC struct {
-	urls    []string
-	client  httpClient
-	log     logger
-	Debug   bool
-	Headers map[string]string // Additional headers to send with the request
-	Timeout time.Duration
-}
-
-// NewBuilderBroadcastRPC create broadcaster rpc client with given url
-func NewBuilderBroadcastRPC(urls []string, options ...func(rpc *BuilderBroadcastRPC)) *BuilderBroadcastRPC {
-	rpc := &BuilderBroadcastRPC{
-		urls:    urls,
-		log:     log.New(os.Stderr, "", log.LstdFlags),
-		Headers: make(map[string]string),
-		Timeout: 30 * time.Second,
-	}
-	for _, option := range options {
-		option(rpc)
-	}
-	rpc.client = &http.Client{
-		Timeout: rpc.Timeout,
-	}
-	return rpc
+// Send a MEV-Share bundle. Unlike eth_sendBundle, it can be valid for a range of blocks and can backrun transactions from the
+// MEV-Share event stream by their hash.
+//
+// https://docs.flashbots.net/flashbots-auction/advanced/rpc-endpoint#mev_sendbundle
+func (rpc *FlashbotsRPC) MevSendBundle(privKey *ecdsa.PrivateKey, param MevSendBundleRequest) (res MevSendBundleResponse, err error) {
+	return rpc.MevSendBundleContext(context.Background(), NewPrivateKeySigner(privKey), param)
 }
 
-// https://docs.flashbots.net/flashbots-auction/searchers/advanced/rpc-endpoint/#eth_sendbundle
-func (broadcaster *BuilderBroadcastRPC) BroadcastBundle(privKey *ecdsa.PrivateKey, param FlashbotsSendBundleRequest) []BuilderBroadcastResponse {
-	requestResponses := broadcaster.broadcastRequest("eth_sendBundle", privKey, param)
-
-	responses := []BuilderBroadcastResponse{}
-
-	for _, requestResponse := range requestResponses {
-		if requestResponse.Err != nil {
-			responses = append(responses, BuilderBroadcastResponse{Err: requestResponse.Err})
-		} else {
-			fbResponse := FlashbotsSendBundleResponse{}
-			err := json.Unmarshal(requestResponse.Msg, &fbResponse)
-			responses = append(responses, BuilderBroadcastResponse{BundleResponse: fbResponse, Err: err})
-		}
+// MevSendBundleContext is like MevSendBundle but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) MevSendBundleContext(ctx context.Context, signer Signer, param MevSendBundleRequest) (res MevSendBundleResponse, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "mev_sendBundle", signer, param)
+	if err != nil {
+		return res, err
 	}
-
-	return responses
+	err = json.Unmarshal(rawMsg, &res)
+	return res, err
 }
 
-type broadcastRequestResponse struct {
-	Msg json.RawMessage
-	Err error
+// Simulate a MEV-Share bundle. Bodies with a transaction hash can only be simulated if the transaction is known to the relay.
+//
+// https://docs.flashbots.net/flashbots-auction/advanced/rpc-endpoint#mev_simbundle
+func (rpc *FlashbotsRPC) MevSimBundle(privKey *ecdsa.PrivateKey, bundle MevSendBundleRequest, overrides MevSimBundleOverrides) (res MevSimBundleResponse, err error) {
+	return rpc.MevSimBundleContext(context.Background(), NewPrivateKeySigner(privKey), bundle, overrides)
 }
 
-func (broadcaster *BuilderBroadcastRPC) broadcastRequest(method string, privKey *ecdsa.PrivateKey, params ...interface{}) []broadcastRequestResponse {
-	request := rpcRequest{
-		ID:      1,
-		JSONRPC: "2.0",
-		Method:  method,
-		Params:  params,
-	}
-
-	body, err := json.Marshal(request)
-	if err != nil {
-		responseArr := []broadcastRequestResponse{{Msg: nil, Err: err}}
-		return responseArr
-	}
-
-	hashedBody := crypto.Keccak256Hash([]byte(body)).Hex()
-	sig, err := crypto.Sign(accounts.TextHash([]byte(hashedBody)), privKey)
+// MevSimBundleContext is like MevSimBundle but takes a context for cancellation and deadlines.
+func (rpc *FlashbotsRPC) MevSimBundleContext(ctx context.Context, signer Signer, bundle MevSendBundleRequest, overrides MevSimBundleOverrides) (res MevSimBundleResponse, err error) {
+	rawMsg, err := rpc.CallWithFlashbotsSignatureContext(ctx, "mev_simBundle", signer, bundle, overrides)
 	if err != nil {
-		responseArr := []broadcastRequestResponse{{Msg: nil, Err: err}}
-		return responseArr
-	}
-
-	signature := crypto.PubkeyToAddress(privKey.PublicKey).Hex() + ":" + hexutil.Encode(sig)
-
-	var wg sync.WaitGroup
-	responseCh := make(chan []byte)
-
-	// Iterate over the URLs and send requests concurrently
-	for _, url := range broadcaster.urls {
-		wg.Add(1)
-
-		go func(url string) {
-			defer wg.Done()
-
-			// Create a new HTTP GET request
-			req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
-			if err != nil {
-				return
-			}
-
-			req.Header.Add("Content-Type", "application/json")
-			req.Header.Add("Accept", "application/json")
-			req.Header.Add("X-Flashbots-Signature", signature)
-			for k, v := range broadcaster.Headers {
-				req.Header.Add(k, v)
-			}
-
-			response, err := broadcaster.client.Do(req)
-			if response != nil {
-				defer response.Body.Close()
-			}
-			if err != nil {
-				return
-			}
-
-			// Read the response body
-			body, err := ioutil.ReadAll(response.Body)
-			if err != nil {
-				return
-			}
-
-			// Send the response body through the channel
-			responseCh <- body
-
-			if broadcaster.Debug {
-				broadcaster.log.Println(fmt.Sprintf("%s\nRequest: %s\nSignature: %s\nResponse: %s\n", method, body, signature, string(body)))
-			}
-
-		}(url)
-	}
-
-	go func() {
-		wg.Wait()
-		close(responseCh)
-	}()
-
-	responses := []broadcastRequestResponse{}
-	for data := range responseCh {
-		// On error, response looks like this instead of JSON-RPC: {"error":"block param must be a hex int"}
-		errorResp := new(RelayErrorResponse)
-		if err := json.Unmarshal(data, errorResp); err == nil && errorResp.Error != "" {
-			// relay returned an error
-			responses = append(responses, broadcastRequestResponse{
-				Msg: nil,
-				Err: fmt.Errorf("%w: %s", ErrRelayErrorResponse, errorResp.Error),
-			})
-			continue
-		}
-
-		resp := new(rpcResponse)
-		if err := json.Unmarshal(data, resp); err != nil {
-			responses = append(responses, broadcastRequestResponse{
-				Msg: nil,
-				Err: err,
-			})
-			continue
-		}
-
-		if resp.Error != nil {
-			responses = append(responses, broadcastRequestResponse{
-				Msg: nil,
-				Err: fmt.Errorf("%w: %s", ErrRelayErrorResponse, (*resp).Error.Message),
-			})
-			continue
-		}
-
-		responses = append(responses, broadcastRequestResponse{
-			Msg: resp.Result,
-			Err: nil,
-		})
+		return res, err
 	}
-
-	return responses
+	err = json.Unmarshal(rawMsg, &res)
+	return res, err
 }
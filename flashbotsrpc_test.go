@@ -3,6 +3,7 @@ package flashbotsrpc
 //lint:file-ignore SA4006 ignore for now
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
@@ -120,6 +122,9 @@ func (s *FlashbotsRPCTestSuite) TestCall() {
 	})
 	_, err = s.rpc.Call("test")
 	s.Require().NotNil(err)
+	var httpErr *HTTPError
+	s.Require().True(errors.As(err, &httpErr))
+	s.Require().Equal("{213", httpErr.Body)
 	httpmock.Reset()
 
 	// Test eth error
@@ -548,6 +553,40 @@ func (s *FlashbotsRPCTestSuite) TestSendTransaction() {
 	s.Require().Equal(result, txid)
 }
 
+func (s *FlashbotsRPCTestSuite) TestSendTypedTransaction() {
+	t := T{
+		From:                 "0x3cc1a3c082944b9dba70e490e481dd56",
+		To:                   "0x1bf21cb1dc384d019a885a06973f7308",
+		Gas:                  21000,
+		Value:                big.NewInt(1),
+		Type:                 2,
+		ChainID:              big.NewInt(1),
+		MaxFeePerGas:         big.NewInt(5000000000),
+		MaxPriorityFeePerGas: big.NewInt(1000000000),
+		AccessList:           []AccessTuple{},
+	}
+
+	result := "0xea1115eb5"
+	s.registerResponse(fmt.Sprintf(`"%s"`, result), func(body []byte) {
+		s.methodEqual(body, "eth_sendTransaction")
+		s.paramsEqual(body, `[{
+			"from": "0x3cc1a3c082944b9dba70e490e481dd56",
+			"to": "0x1bf21cb1dc384d019a885a06973f7308",
+			"gas": "0x5208",
+			"value": "0x1",
+			"type": "0x2",
+			"chainId": "0x1",
+			"maxFeePerGas": "0x12a05f200",
+			"maxPriorityFeePerGas": "0x3b9aca00",
+			"accessList": []
+		}]`)
+	})
+
+	txid, err := s.rpc.EthSendTransaction(t)
+	s.Require().Nil(err)
+	s.Require().Equal(result, txid)
+}
+
 func (s *FlashbotsRPCTestSuite) TestEthSendRawTransaction() {
 	data := "0xd46e8dd67c5d32be8d46e8dd67c5d32be8058bb8eb970870f072445675058bb8eb970870f072445675"
 	result := "0xe670ec64341771606e55d6b4ca35a1a6b75ee3d5145a99d05921026d1527331"
@@ -575,7 +614,7 @@ func (s *FlashbotsRPCTestSuite) TestEthGetCompilers() {
 
 func (s *FlashbotsRPCTestSuite) TestGetBlock() {
 	s.registerResponseError(errors.New("Error"))
-	block, err := s.rpc.getBlock("eth_getBlockByHash", true)
+	block, err := s.rpc.getBlock(context.Background(), "eth_getBlockByHash", true)
 	s.Require().NotNil(err)
 
 	// Test with transactions
@@ -632,7 +671,7 @@ func (s *FlashbotsRPCTestSuite) TestGetBlock() {
 		s.methodEqual(body, "eth_getBlockByHash")
 	})
 
-	block, err = s.rpc.getBlock("eth_getBlockByHash", true)
+	block, err = s.rpc.getBlock(context.Background(), "eth_getBlockByHash", true)
 	s.Require().Nil(err)
 	s.Require().NotNil(block)
 	s.Require().Equal(hash, block.Hash)
@@ -714,7 +753,7 @@ func (s *FlashbotsRPCTestSuite) TestGetBlock() {
 		s.methodEqual(body, "eth_getBlockByHash")
 	})
 
-	block, err = s.rpc.getBlock("eth_getBlockByHash", false)
+	block, err = s.rpc.getBlock(context.Background(), "eth_getBlockByHash", false)
 	s.Require().Nil(err)
 	s.Require().NotNil(block)
 	s.Require().Equal(hash, block.Hash)
@@ -751,7 +790,7 @@ func (s *FlashbotsRPCTestSuite) TestGetBlock() {
 
 	s.registerResponse("null", func(body []byte) {})
 
-	block, err = s.rpc.getBlock("eth_getBlockByHash", false)
+	block, err = s.rpc.getBlock(context.Background(), "eth_getBlockByHash", false)
 	s.Require().Nil(block)
 	s.Require().Nil(err)
 }
@@ -917,7 +956,7 @@ func (s *FlashbotsRPCTestSuite) TestGetTransaction() {
 		s.methodEqual(body, "ggg")
 	})
 
-	transaction, err := s.rpc.getTransaction("ggg")
+	transaction, err := s.rpc.getTransaction(context.Background(), "ggg")
 	s.Require().Nil(err)
 	s.Require().NotNil(transaction)
 	s.Require().Equal("0x3068bb24a6c65a80eb350b89b2ef2f4d0605f59e5d07fd3467eb76511c4408e7", transaction.Hash)
@@ -1284,10 +1323,10 @@ func TestFlashbotsRPCTestSuite(t *testing.T) {
 
 func TestEthError(t *testing.T) {
 	var err error
-	err = RpcError{-32555, "Messg"}
+	err = RpcError{Code: -32555, Message: "Messg"}
 	require.Equal(t, "Error -32555 (Messg)", err.Error())
 
-	err = RpcError{32847, "Kuku"}
+	err = RpcError{Code: 32847, Message: "Kuku"}
 	require.Equal(t, "Error 32847 (Kuku)", err.Error())
 }
 
@@ -1322,3 +1361,131 @@ func TestCallWithFlashbotsSignature(t *testing.T) {
 	require.Error(t, err, "500 should return an error")
 	require.True(t, errors.Is(err, ErrRelayErrorResponse))
 }
+
+func TestCallContextCancel(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+	rpc := NewFlashbotsRPC(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := rpc.CallContext(ctx, "eth_blockNumber")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = rpc.FlashbotsSendBundleContext(ctx, NewPrivateKeySigner(key), FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	broadcaster := NewBuilderBroadcastRPC([]string{server.URL})
+	results := broadcaster.BroadcastBundleContext(ctx, NewPrivateKeySigner(key), FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	for _, result := range results {
+		require.Error(t, result.Err)
+	}
+}
+
+func TestFlashbotsCancelBundle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		switch gjson.GetBytes(body, "method").String() {
+		case "eth_sendBundle":
+			require.Equal(t, "2f2a2e5e-6e5c-4a6f-9a4e-7b4c2d0c8e11", gjson.GetBytes(body, "params.0.replacementUuid").String())
+			_, err = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x1234"}}`))
+		case "eth_cancelBundle":
+			require.JSONEq(t, `[{"replacementUuid":"2f2a2e5e-6e5c-4a6f-9a4e-7b4c2d0c8e11"}]`, gjson.GetBytes(body, "params").Raw)
+			_, err = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+		default:
+			t.Errorf("unexpected method: %s", body)
+		}
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = rpc.FlashbotsSendBundle(key, FlashbotsSendBundleRequest{
+		Txs:             []string{"0x01"},
+		BlockNumber:     "0x1",
+		ReplacementUUID: "2f2a2e5e-6e5c-4a6f-9a4e-7b4c2d0c8e11",
+	})
+	require.NoError(t, err)
+
+	err = rpc.FlashbotsCancelBundle(key, FlashbotsCancelBundleRequest{ReplacementUUID: "2f2a2e5e-6e5c-4a6f-9a4e-7b4c2d0c8e11"})
+	require.NoError(t, err)
+
+	broadcaster := NewBuilderBroadcastRPC([]string{server.URL, server.URL})
+	results := broadcaster.BroadcastCancelBundle(key, FlashbotsCancelBundleRequest{ReplacementUUID: "2f2a2e5e-6e5c-4a6f-9a4e-7b4c2d0c8e11"})
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.NoError(t, result.Err)
+	}
+}
+
+func TestMevSendBundle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NotEmpty(t, r.Header.Get("X-Flashbots-Signature"))
+
+		switch gjson.GetBytes(body, "method").String() {
+		case "mev_sendBundle":
+			require.JSONEq(t, `[{
+				"version": "v0.1",
+				"inclusion": {"block": "0x10", "maxBlock": "0x14"},
+				"body": [{"hash": "0xab"}, {"tx": "0x01", "canRevert": true}],
+				"validity": {"refund": [{"bodyIdx": 0, "percent": 90}]},
+				"privacy": {"hints": ["calldata", "logs"], "builders": ["flashbots"]}
+			}]`, gjson.GetBytes(body, "params").Raw)
+			_, err = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x0000000000000000000000000000000000000000000000000000000000001234"}}`))
+		case "mev_simBundle":
+			require.Equal(t, "0x10", gjson.GetBytes(body, "params.0.inclusion.block").String())
+			require.JSONEq(t, `{"parentBlock":"latest","timestamp":"0x64"}`, gjson.GetBytes(body, "params.1").Raw)
+			_, err = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{
+				"success": true,
+				"stateBlock": "0xf",
+				"mevGasPrice": "0x3b9aca00",
+				"profit": "0x2386f26fc10000",
+				"refundableValue": "0x2386f26fc10000",
+				"gasUsed": "0xa410",
+				"logs": [{"txLogs": [{"address": "0x0000000000000000000000000000000000000001", "topics": [], "data": "0x"}]}, {}]
+			}}`))
+		default:
+			t.Errorf("unexpected method: %s", body)
+		}
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bundle := MevSendBundleRequest{
+		Version:   "v0.1",
+		Inclusion: MevBundleInclusion{BlockNumber: 16, MaxBlock: 20},
+		Body:      []MevBundleBody{{Hash: "0xab"}, {Tx: "0x01", CanRevert: true}},
+		Validity:  &MevBundleValidity{Refund: []MevBundleRefund{{BodyIdx: 0, Percent: 90}}},
+		Privacy:   &MevBundlePrivacy{Hints: []string{MevShareHintCalldata, MevShareHintLogs}, Builders: []string{"flashbots"}},
+	}
+
+	sendResult, err := rpc.MevSendBundle(key, bundle)
+	require.NoError(t, err)
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000001234", sendResult.BundleHash.Hex())
+
+	timestamp := hexutil.Uint64(100)
+	simResult, err := rpc.MevSimBundle(key, bundle, MevSimBundleOverrides{ParentBlock: "latest", Timestamp: &timestamp})
+	require.NoError(t, err)
+	require.True(t, simResult.Success)
+	require.Equal(t, uint64(15), uint64(simResult.StateBlock))
+	require.Equal(t, "10000000000000000", simResult.Profit.ToInt().String())
+	require.Equal(t, uint64(42000), uint64(simResult.GasUsed))
+	require.Len(t, simResult.BodyLogs, 2)
+	require.Len(t, simResult.BodyLogs[0].TxLogs, 1)
+}
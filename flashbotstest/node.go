@@ -0,0 +1,187 @@
+package flashbotstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/metachris/flashbotsrpc"
+)
+
+// Handler returns the result of a request with the given parameters. If it returns a Fault, the node responds with
+// the fault instead. Other errors are sent as JSON-RPC errors with CodeServerError.
+type Handler func(params []json.RawMessage) (interface{}, error)
+
+// Node is a mock JSON-RPC server, like an Ethereum node or a builder, which answers every method with its Handler.
+// Methods without a handler are answered with CodeMethodNotFound.
+//
+//	node := flashbotstest.NewNode()
+//	defer node.Close()
+//	node.SetResult("eth_blockNumber", "0x64")
+//
+// It handles batches, and records all requests. Requests don't have to be signed, but requests with an invalid
+// X-Flashbots-Signature header are rejected like by the relay. Faults with a code are JSON-RPC errors of their request,
+// faults without a code, with a body or with a delay answer the whole HTTP request, also in a batch.
+//
+// Node is safe for concurrent use, its handlers are called one at a time.
+type Node struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	handlers map[string]Handler
+	faults   map[string][]Fault
+	requests []Request
+}
+
+// nodeRequest is a JSON-RPC request received by a node
+type nodeRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// NewNode starts a mock node without handlers. It should be closed with Close.
+func NewNode() *Node {
+	node := &Node{
+		handlers: make(map[string]Handler),
+		faults:   make(map[string][]Fault),
+	}
+	node.Server = httptest.NewServer(http.HandlerFunc(node.handle))
+	return node
+}
+
+// Handle makes the node answer requests of the method with the handler. The handler of AnyMethod answers all methods
+// without their own handler. Handlers must not call methods of the node.
+func (node *Node) Handle(method string, handler Handler) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	node.handlers[method] = handler
+}
+
+// SetResult makes the node respond to all requests of the method with the given result
+func (node *Node) SetResult(method string, result interface{}) {
+	node.Handle(method, func([]json.RawMessage) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// SetFault makes the node respond to all requests of the method with the fault
+func (node *Node) SetFault(method string, fault Fault) {
+	node.Handle(method, func([]json.RawMessage) (interface{}, error) {
+		return nil, fault
+	})
+}
+
+// InjectFault makes the node respond to the next requests of the method with the given faults, one per request and in
+// order, before calling the handler again. Faults for a method are used before faults for AnyMethod.
+func (node *Node) InjectFault(method string, faults ...Fault) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	node.faults[method] = append(node.faults[method], faults...)
+}
+
+// Requests returns all requests, one per request of a batch
+func (node *Node) Requests() []Request {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	return append([]Request{}, node.requests...)
+}
+
+func (node *Node) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, flashbotsrpc.RelayErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var signer common.Address
+	if header := r.Header.Get("X-Flashbots-Signature"); header != "" {
+		if signer, err = flashbotsrpc.VerifyFlashbotsSignature(header, body); err != nil {
+			writeJSON(w, http.StatusForbidden, flashbotsrpc.RelayErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+	var requests []nodeRequest
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		requests = make([]nodeRequest, 1)
+		err = json.Unmarshal(body, &requests[0])
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, flashbotsrpc.RelayErrorResponse{Error: "invalid JSON-RPC request: " + err.Error()})
+		return
+	}
+
+	responses, statusCode, fault := node.handleRequests(requests, signer, r.Header)
+	if fault != nil {
+		writeFault(w, r, requests[0].ID, *fault)
+		return
+	}
+	if batch {
+		writeJSON(w, statusCode, responses)
+	} else {
+		writeJSON(w, statusCode, responses[0])
+	}
+}
+
+// handleRequests records the requests and returns their responses and the HTTP status code, or the fault which answers
+// the whole HTTP request
+func (node *Node) handleRequests(requests []nodeRequest, signer common.Address, header http.Header) ([]interface{}, int, *Fault) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	for _, request := range requests {
+		var params json.RawMessage
+		if len(request.Params) > 0 {
+			params = request.Params[0]
+		}
+		node.requests = append(node.requests, Request{Method: request.Method, Params: params, Signer: signer, Header: header.Clone()})
+	}
+
+	statusCode := http.StatusOK
+	responses := make([]interface{}, len(requests))
+	for i, request := range requests {
+		result, err := node.handleMethod(request.Method, request.Params)
+		var fault Fault
+		switch {
+		case err == nil:
+			responses[i] = map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result}
+			continue
+		case !errors.As(err, &fault):
+			fault = Fault{Code: CodeServerError, Message: err.Error()}
+		case fault.Code == 0 || fault.Body != "" || fault.Delay > 0:
+			return nil, 0, &fault
+		}
+
+		if fault.StatusCode != 0 {
+			statusCode = fault.StatusCode
+		}
+		responses[i] = rpcErrorResponse(request.ID, fault.Code, fault.Message)
+	}
+	return responses, statusCode, nil
+}
+
+// handleMethod returns the result of the next injected fault or the handler of the method. The lock must be held.
+func (node *Node) handleMethod(method string, params []json.RawMessage) (interface{}, error) {
+	if fault, ok := nextFault(node.faults, method); ok {
+		return nil, fault
+	}
+
+	handler, ok := node.handlers[method]
+	if !ok {
+		handler, ok = node.handlers[AnyMethod]
+	}
+	if !ok {
+		return nil, Fault{Code: CodeMethodNotFound, Message: fmt.Sprintf("the method %s does not exist/is not available", method)}
+	}
+	return handler(params)
+}
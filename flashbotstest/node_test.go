@@ -0,0 +1,124 @@
+package flashbotstest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNode(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+
+	node.SetResult("eth_blockNumber", "0x64")
+	node.Handle("eth_getBalance", func(params []json.RawMessage) (interface{}, error) {
+		require.Len(t, params, 2)
+		require.JSONEq(t, `"latest"`, string(params[1]))
+		return "0x1", nil
+	})
+	rpc := flashbotsrpc.New(node.URL)
+
+	blockNumber, err := rpc.EthBlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, 100, blockNumber)
+	balance, err := rpc.EthGetBalance("0x407d73d8a49eeb85d32cf465507dd71d507100c1", "latest")
+	require.NoError(t, err)
+	require.Equal(t, "1", balance.String())
+
+	// Methods without a handler don't exist
+	_, err = rpc.EthGasPrice()
+	require.Equal(t, flashbotstest.CodeMethodNotFound, err.(flashbotsrpc.RpcError).Code)
+	node.SetResult(flashbotstest.AnyMethod, "0x2")
+	gasPrice, err := rpc.EthGasPrice()
+	require.NoError(t, err)
+	require.Equal(t, "2", gasPrice.String())
+
+	requests := node.Requests()
+	require.Len(t, requests, 4)
+	require.Equal(t, "eth_getBalance", requests[1].Method)
+	require.JSONEq(t, `"0x407d73d8a49eeb85d32cf465507dd71d507100c1"`, string(requests[1].Params))
+}
+
+func TestNodeBatch(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+
+	node.SetResult("eth_blockNumber", "0x64")
+	node.SetFault("eth_chainId", flashbotstest.Fault{Code: flashbotstest.CodeServerError, Message: "not synced"})
+	rpc := flashbotsrpc.New(node.URL)
+
+	var blockNumber string
+	batch := []flashbotsrpc.BatchElem{
+		{Method: "eth_blockNumber", Result: &blockNumber},
+		{Method: "eth_chainId"},
+	}
+	require.NoError(t, rpc.BatchCall(batch))
+	require.NoError(t, batch[0].Error)
+	require.Equal(t, "0x64", blockNumber)
+	require.Error(t, batch[1].Error)
+	require.Contains(t, batch[1].Error.Error(), "not synced")
+
+	// Faults without a code fail the whole batch
+	node.InjectFault("eth_blockNumber", flashbotstest.Fault{StatusCode: http.StatusBadGateway, Body: "<html>bad gateway</html>"})
+	err := rpc.BatchCall(batch)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bad gateway")
+	require.Len(t, node.Requests(), 4)
+}
+
+func TestNodeFaults(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+
+	node.SetResult("eth_blockNumber", "0x64")
+	node.InjectFault(flashbotstest.AnyMethod,
+		flashbotstest.Fault{StatusCode: http.StatusTooManyRequests, Message: "rate limit exceeded"},
+		flashbotstest.Fault{StatusCode: http.StatusGatewayTimeout, Delay: time.Second},
+	)
+	rpc := flashbotsrpc.New(node.URL)
+
+	_, err := rpc.EthBlockNumber()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "rate limit exceeded")
+
+	// The client doesn't wait for the delay
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = rpc.EthBlockNumberContext(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Faults are used up
+	blockNumber, err := rpc.EthBlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, 100, blockNumber)
+}
+
+func TestNodeSignature(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	res, err := flashbotsrpc.New(node.URL).FlashbotsSendBundle(key, flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.NoError(t, err)
+	require.Equal(t, "0x1234", res.BundleHash)
+
+	// Invalid signatures are rejected
+	rpc := flashbotsrpc.New(node.URL)
+	rpc.Headers["X-Flashbots-Signature"] = crypto.PubkeyToAddress(key.PublicKey).Hex() + ":0x1234"
+	_, err = rpc.Call("eth_sendBundle")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid X-Flashbots-Signature header")
+
+	requests := node.Requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), requests[0].Signer)
+}
@@ -0,0 +1,428 @@
+// Package flashbotstest provides an in-process mock of the Flashbots relay, for testing code which uses flashbotsrpc.
+//
+//	relay := flashbotstest.NewRelay()
+//	defer relay.Close()
+//
+//	rpc := flashbotsrpc.New(relay.URL)
+//	// ... code under test ...
+//	bundles := relay.Bundles()
+package flashbotstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+)
+
+// AnyMethod matches requests of every method in InjectFault
+const AnyMethod = ""
+
+// JSON-RPC error codes used by the relay
+const (
+	CodeInvalidParams  = -32602
+	CodeMethodNotFound = -32601
+	CodeServerError    = -32000
+)
+
+// Request is a request received by the relay, with a valid signature
+type Request struct {
+	Method string
+	Params json.RawMessage // The first parameter
+	Signer common.Address  // Address the X-Flashbots-Signature header recovers to
+	Header http.Header
+}
+
+// Fault is an error the relay or a node responds with instead of handling a request
+type Fault struct {
+	StatusCode int           // HTTP status code, 200 if not set
+	Code       int           // JSON-RPC error code. If not set, Message is sent as a relay error: {"error": "<Message>"}
+	Message    string        // Error message
+	Body       string        // (Optional) Raw response body, sent instead of the error, e.g. the HTML page of a gateway
+	Delay      time.Duration // (Optional) Wait before responding, or until the client goes away
+}
+
+func (fault Fault) Error() string {
+	return fault.Message
+}
+
+// Relay is a mock Flashbots relay, which implements eth_sendBundle, eth_callBundle, eth_cancelBundle,
+// eth_sendPrivateTransaction, eth_cancelPrivateTransaction, flashbots_getUserStats and flashbots_getBundleStats(V2).
+//
+// It rejects requests without a valid X-Flashbots-Signature header like the relay, with HTTP 403 and a relay error,
+// and records all other requests. Relay is safe for concurrent use.
+type Relay struct {
+	*httptest.Server
+
+	lock                sync.Mutex
+	requests            []Request
+	bundles             []flashbotsrpc.FlashbotsSendBundleRequest
+	bundlesReceivedAt   map[string]time.Time // By bundle hash
+	callBundles         []flashbotsrpc.FlashbotsCallBundleParam
+	cancelledBundles    []string
+	privateTxs          []flashbotsrpc.FlashbotsSendPrivateTransactionRequest
+	cancelledPrivateTxs map[string]bool // By tx hash, false if the transaction was received but not cancelled
+	faults              map[string][]Fault
+	results             map[string]interface{}
+}
+
+// NewRelay starts a mock relay. It should be closed with Close.
+func NewRelay() *Relay {
+	relay := &Relay{
+		bundlesReceivedAt:   make(map[string]time.Time),
+		cancelledPrivateTxs: make(map[string]bool),
+		faults:              make(map[string][]Fault),
+		results:             make(map[string]interface{}),
+	}
+	relay.Server = httptest.NewServer(http.HandlerFunc(relay.handle))
+	return relay
+}
+
+// InjectFault makes the relay respond to the next requests of the method with the given faults, one per request and
+// in order. Faults for a method are used before faults for AnyMethod.
+func (relay *Relay) InjectFault(method string, faults ...Fault) {
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+	relay.faults[method] = append(relay.faults[method], faults...)
+}
+
+// SetResult makes the relay respond to all requests of the method with the given result, instead of the default result
+func (relay *Relay) SetResult(method string, result interface{}) {
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+	relay.results[method] = result
+}
+
+// Requests returns all requests with a valid signature, including the ones answered with a fault
+func (relay *Relay) Requests() []Request {
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+	return append([]Request{}, relay.requests...)
+}
+
+// Bundles returns the bundles received with eth_sendBundle
+func (relay *Relay) Bundles() []flashbotsrpc.FlashbotsSendBundleRequest {
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+	return append([]flashbotsrpc.FlashbotsSendBundleRequest{}, relay.bundles...)
+}
+
+// CallBundles returns the bundles received with eth_callBundle
+func (relay *Relay) CallBundles() []flashbotsrpc.FlashbotsCallBundleParam {
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+	return append([]flashbotsrpc.FlashbotsCallBundleParam{}, relay.callBundles...)
+}
+
+// CancelledBundles returns the replacement UUIDs received with eth_cancelBundle
+func (relay *Relay) CancelledBundles() []string {
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+	return append([]string{}, relay.cancelledBundles...)
+}
+
+// PrivateTransactions returns the transactions received with eth_sendPrivateTransaction
+func (relay *Relay) PrivateTransactions() []flashbotsrpc.FlashbotsSendPrivateTransactionRequest {
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+	return append([]flashbotsrpc.FlashbotsSendPrivateTransactionRequest{}, relay.privateTxs...)
+}
+
+// IsPrivateTransactionCancelled returns whether the private transaction with the given hash was cancelled
+func (relay *Relay) IsPrivateTransactionCancelled(txHash common.Hash) bool {
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+	return relay.cancelledPrivateTxs[txHash.Hex()]
+}
+
+func (relay *Relay) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, flashbotsrpc.RelayErrorResponse{Error: err.Error()})
+		return
+	}
+
+	signer, err := flashbotsrpc.VerifyFlashbotsSignature(r.Header.Get("X-Flashbots-Signature"), body)
+	if err != nil {
+		writeJSON(w, http.StatusForbidden, flashbotsrpc.RelayErrorResponse{Error: err.Error()})
+		return
+	}
+
+	request := struct {
+		ID     int               `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeJSON(w, http.StatusBadRequest, flashbotsrpc.RelayErrorResponse{Error: "invalid JSON-RPC request: " + err.Error()})
+		return
+	}
+	var params json.RawMessage
+	if len(request.Params) > 0 {
+		params = request.Params[0]
+	}
+
+	relay.lock.Lock()
+	relay.requests = append(relay.requests, Request{Method: request.Method, Params: params, Signer: signer, Header: r.Header.Clone()})
+	fault, faulted := nextFault(relay.faults, request.Method)
+	relay.lock.Unlock()
+
+	if faulted {
+		writeFault(w, r, request.ID, fault)
+		return
+	}
+
+	relay.lock.Lock()
+	defer relay.lock.Unlock()
+
+	result, rpcErr := relay.handleMethod(request.Method, params)
+	if rpcErr != nil {
+		writeJSON(w, http.StatusOK, rpcErrorResponse(request.ID, rpcErr.Code, rpcErr.Message))
+		return
+	}
+	if custom, ok := relay.results[request.Method]; ok {
+		result = custom
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"result":  result,
+	})
+}
+
+// nextFault removes and returns the next injected fault for the method, if any. The lock of the faults must be held.
+func nextFault(faults map[string][]Fault, method string) (Fault, bool) {
+	for _, key := range []string{method, AnyMethod} {
+		if next := faults[key]; len(next) > 0 {
+			faults[key] = next[1:]
+			return next[0], true
+		}
+	}
+	return Fault{}, false
+}
+
+// writeFault responds with the fault after its delay. The error of a request with the given ID is sent as a JSON-RPC
+// error if the fault has a code.
+func writeFault(w http.ResponseWriter, r *http.Request, id interface{}, fault Fault) {
+	if fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	statusCode := fault.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	switch {
+	case fault.Body != "":
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(fault.Body))
+	case fault.Code == 0:
+		writeJSON(w, statusCode, flashbotsrpc.RelayErrorResponse{Error: fault.Message})
+	default:
+		writeJSON(w, statusCode, rpcErrorResponse(id, fault.Code, fault.Message))
+	}
+}
+
+// handleMethod records the request and returns the default result. The lock must be held.
+func (relay *Relay) handleMethod(method string, params json.RawMessage) (interface{}, *flashbotsrpc.RpcError) {
+	switch method {
+	case "eth_sendBundle":
+		var bundle flashbotsrpc.FlashbotsSendBundleRequest
+		if err := json.Unmarshal(params, &bundle); err != nil {
+			return nil, invalidParams(err)
+		}
+		txs, err := decodeTxs(bundle.Txs)
+		if err != nil {
+			return nil, invalidParams(err)
+		}
+
+		hash := bundleHash(txs)
+		relay.bundles = append(relay.bundles, bundle)
+		relay.bundlesReceivedAt[hash.Hex()] = time.Now()
+		return flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: hash.Hex()}, nil
+
+	case "eth_callBundle":
+		var bundle flashbotsrpc.FlashbotsCallBundleParam
+		if err := json.Unmarshal(params, &bundle); err != nil {
+			return nil, invalidParams(err)
+		}
+		txs, err := decodeTxs(bundle.Txs)
+		if err != nil {
+			return nil, invalidParams(err)
+		}
+
+		relay.callBundles = append(relay.callBundles, bundle)
+		return callBundleResponse(bundle, txs), nil
+
+	case "eth_cancelBundle":
+		var cancel flashbotsrpc.FlashbotsCancelBundleRequest
+		if err := json.Unmarshal(params, &cancel); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		relay.cancelledBundles = append(relay.cancelledBundles, cancel.ReplacementUUID)
+		return nil, nil
+
+	case "eth_sendPrivateTransaction":
+		var privateTx flashbotsrpc.FlashbotsSendPrivateTransactionRequest
+		if err := json.Unmarshal(params, &privateTx); err != nil {
+			return nil, invalidParams(err)
+		}
+		txs, err := decodeTxs([]string{privateTx.Tx})
+		if err != nil {
+			return nil, invalidParams(err)
+		}
+
+		hash := txs[0].Hash().Hex()
+		relay.privateTxs = append(relay.privateTxs, privateTx)
+		relay.cancelledPrivateTxs[hash] = false
+		return hash, nil
+
+	case "eth_cancelPrivateTransaction":
+		var cancel flashbotsrpc.FlashbotsCancelPrivateTransactionRequest
+		if err := json.Unmarshal(params, &cancel); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		hash := common.HexToHash(cancel.TxHash).Hex()
+		cancelled, ok := relay.cancelledPrivateTxs[hash]
+		if !ok {
+			return nil, &flashbotsrpc.RpcError{Code: CodeServerError, Message: "tx not found"}
+		}
+		if cancelled {
+			return nil, &flashbotsrpc.RpcError{Code: CodeServerError, Message: "tx was already cancelled"}
+		}
+		relay.cancelledPrivateTxs[hash] = true
+		return true, nil
+
+	case "flashbots_getUserStats":
+		return flashbotsrpc.FlashbotsUserStats{
+			AllTimeMinerPayments: "0",
+			AllTimeGasSimulated:  "0",
+			Last7dMinerPayments:  "0",
+			Last7dGasSimulated:   "0",
+			Last1dMinerPayments:  "0",
+			Last1dGasSimulated:   "0",
+		}, nil
+
+	case "flashbots_getBundleStats", "flashbots_getBundleStatsV2":
+		var param flashbotsrpc.FlashbotsGetBundleStatsParam
+		if err := json.Unmarshal(params, &param); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		receivedAt, ok := relay.bundlesReceivedAt[common.HexToHash(param.BundleHash).Hex()]
+		if !ok {
+			return nil, &flashbotsrpc.RpcError{Code: CodeServerError, Message: "bundle not found"}
+		}
+		if method == "flashbots_getBundleStats" {
+			return flashbotsrpc.FlashbotsGetBundleStatsResponse{
+				IsSimulated:    true,
+				IsSentToMiners: true,
+				SimulatedAt:    receivedAt,
+				SubmittedAt:    receivedAt,
+				SentToMinersAt: receivedAt,
+			}, nil
+		}
+		return flashbotsrpc.FlashbotsGetBundleStatsResponseV2{
+			IsSimulated: true,
+			SimulatedAt: receivedAt,
+			ReceivedAt:  receivedAt,
+		}, nil
+	}
+
+	return nil, &flashbotsrpc.RpcError{Code: CodeMethodNotFound, Message: fmt.Sprintf("the method %s does not exist/is not available", method)}
+}
+
+// callBundleResponse returns a successful simulation of the bundle, where every transaction uses all its gas and pays nothing
+func callBundleResponse(bundle flashbotsrpc.FlashbotsCallBundleParam, txs []*types.Transaction) flashbotsrpc.FlashbotsCallBundleResponse {
+	zero := flashbotsrpc.NewBigIntString(new(big.Int))
+	res := flashbotsrpc.FlashbotsCallBundleResponse{
+		BundleGasPrice:    zero,
+		BundleHash:        bundleHash(txs).Hex(),
+		CoinbaseDiff:      zero,
+		EthSentToCoinbase: zero,
+		GasFees:           zero,
+		Results:           []flashbotsrpc.FlashbotsCallBundleResult{},
+	}
+
+	if blockNumber, err := hexutil.DecodeUint64(bundle.BlockNumber); err == nil && blockNumber > 0 {
+		res.StateBlockNumber = int64(blockNumber - 1)
+	}
+
+	for _, tx := range txs {
+		from, _ := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		res.Results = append(res.Results, flashbotsrpc.FlashbotsCallBundleResult{
+			CoinbaseDiff:      zero,
+			EthSentToCoinbase: zero,
+			FromAddress:       from,
+			GasFees:           zero,
+			GasPrice:          zero,
+			GasUsed:           int64(tx.Gas()),
+			ToAddress:         tx.To(),
+			TxHash:            tx.Hash(),
+			Value:             hexutil.Bytes{},
+		})
+		res.TotalGasUsed += int64(tx.Gas())
+	}
+	return res
+}
+
+// decodeTxs decodes raw signed transactions
+func decodeTxs(rawTxs []string) ([]*types.Transaction, error) {
+	txs := make([]*types.Transaction, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		data, err := hexutil.Decode(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+
+		txs[i] = new(types.Transaction)
+		if err := txs[i].UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+	}
+	return txs, nil
+}
+
+// bundleHash returns the hash of a bundle like the relay: the keccak256 hash of the concatenated transaction hashes
+func bundleHash(txs []*types.Transaction) common.Hash {
+	hashes := make([]byte, 0, len(txs)*common.HashLength)
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+func invalidParams(err error) *flashbotsrpc.RpcError {
+	return &flashbotsrpc.RpcError{Code: CodeInvalidParams, Message: err.Error()}
+}
+
+func rpcErrorResponse(id interface{}, code int, message string) interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error":   flashbotsrpc.RpcError{Code: code, Message: message},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,166 @@
+package flashbotstest_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+func newSignedTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64) (string, common.Hash) {
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	}), types.LatestSignerForChainID(big.NewInt(1)), key)
+	require.NoError(t, err)
+
+	data, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return hexutil.Encode(data), tx.Hash()
+}
+
+func TestRelayBundles(t *testing.T) {
+	relay := flashbotstest.NewRelay()
+	defer relay.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	rawTx, txHash := newSignedTx(t, key, 0)
+	rpc := flashbotsrpc.New(relay.URL)
+
+	bundle := flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{rawTx}, BlockNumber: "0x64", ReplacementUUID: "1e7c3ae0-5a8e-4b1e-9a0d-3b6f1c0f7a11"}
+	res, err := rpc.FlashbotsSendBundle(key, bundle)
+	require.NoError(t, err)
+	require.Equal(t, crypto.Keccak256Hash(txHash.Bytes()).Hex(), res.BundleHash)
+	require.Equal(t, []flashbotsrpc.FlashbotsSendBundleRequest{bundle}, relay.Bundles())
+
+	stats, err := rpc.FlashbotsGetBundleStatsV2(key, flashbotsrpc.FlashbotsGetBundleStatsParam{BlockNumber: "0x64", BundleHash: res.BundleHash})
+	require.NoError(t, err)
+	require.True(t, stats.IsSimulated)
+	require.False(t, stats.ReceivedAt.IsZero())
+
+	_, err = rpc.FlashbotsGetBundleStats(key, flashbotsrpc.FlashbotsGetBundleStatsParam{BlockNumber: "0x64", BundleHash: common.Hash{}.Hex()})
+	require.ErrorIs(t, err, flashbotsrpc.ErrRelayErrorResponse)
+
+	callRes, err := rpc.FlashbotsCallBundle(key, flashbotsrpc.FlashbotsCallBundleParam{Txs: []string{rawTx}, BlockNumber: "0x64", StateBlockNumber: "latest"})
+	require.NoError(t, err)
+	require.Equal(t, res.BundleHash, callRes.BundleHash)
+	require.Equal(t, int64(99), callRes.StateBlockNumber)
+	require.Len(t, callRes.Results, 1)
+	require.Equal(t, txHash, callRes.Results[0].TxHash)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), callRes.Results[0].FromAddress)
+	require.Len(t, relay.CallBundles(), 1)
+
+	require.NoError(t, rpc.FlashbotsCancelBundle(key, flashbotsrpc.FlashbotsCancelBundleRequest{ReplacementUUID: bundle.ReplacementUUID}))
+	require.Equal(t, []string{bundle.ReplacementUUID}, relay.CancelledBundles())
+
+	_, err = rpc.FlashbotsGetUserStats(key, 100)
+	require.NoError(t, err)
+
+	requests := relay.Requests()
+	require.Len(t, requests, 6)
+	require.Equal(t, "eth_sendBundle", requests[0].Method)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), requests[0].Signer)
+}
+
+func TestRelayPrivateTransactions(t *testing.T) {
+	relay := flashbotstest.NewRelay()
+	defer relay.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	rawTx, txHash := newSignedTx(t, key, 0)
+	rpc := flashbotsrpc.New(relay.URL)
+
+	hash, err := rpc.FlashbotsSendPrivateTransaction(key, flashbotsrpc.FlashbotsSendPrivateTransactionRequest{Tx: rawTx})
+	require.NoError(t, err)
+	require.Equal(t, txHash.Hex(), hash)
+	require.Len(t, relay.PrivateTransactions(), 1)
+	require.False(t, relay.IsPrivateTransactionCancelled(txHash))
+
+	cancelled, err := rpc.FlashbotsCancelPrivateTransaction(key, flashbotsrpc.FlashbotsCancelPrivateTransactionRequest{TxHash: hash})
+	require.NoError(t, err)
+	require.True(t, cancelled)
+	require.True(t, relay.IsPrivateTransactionCancelled(txHash))
+
+	_, err = rpc.FlashbotsCancelPrivateTransaction(key, flashbotsrpc.FlashbotsCancelPrivateTransactionRequest{TxHash: hash})
+	require.ErrorIs(t, err, flashbotsrpc.ErrTxAlreadyCancelled)
+	require.Contains(t, err.Error(), "tx was already cancelled")
+
+	_, err = rpc.FlashbotsCancelPrivateTransaction(key, flashbotsrpc.FlashbotsCancelPrivateTransactionRequest{TxHash: common.Hash{}.Hex()})
+	require.ErrorIs(t, err, flashbotsrpc.ErrTxNotFound)
+	require.Contains(t, err.Error(), "tx not found")
+}
+
+func TestRelaySignature(t *testing.T) {
+	relay := flashbotstest.NewRelay()
+	defer relay.Close()
+
+	// Unsigned requests are rejected
+	_, err := flashbotsrpc.New(relay.URL).Call("flashbots_getUserStats", "0x64")
+	require.Error(t, err)
+
+	// Signatures are checked against the address in the header
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sig, err := crypto.Sign(crypto.Keccak256([]byte("other body")), key)
+	require.NoError(t, err)
+	rpc := flashbotsrpc.New(relay.URL)
+	rpc.Headers["X-Flashbots-Signature"] = crypto.PubkeyToAddress(key.PublicKey).Hex() + ":" + hexutil.Encode(sig)
+	_, err = rpc.Call("flashbots_getUserStats", "0x64")
+	require.Error(t, err)
+
+	require.Empty(t, relay.Requests())
+}
+
+func TestRelayFaults(t *testing.T) {
+	relay := flashbotstest.NewRelay()
+	defer relay.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	rawTx, _ := newSignedTx(t, key, 0)
+	rpc := flashbotsrpc.New(relay.URL)
+	bundle := flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{rawTx}, BlockNumber: "0x64"}
+
+	relay.InjectFault("eth_sendBundle",
+		flashbotstest.Fault{StatusCode: http.StatusTooManyRequests, Message: "rate limited"},
+		flashbotstest.Fault{Code: flashbotstest.CodeServerError, Message: "bundle rejected"},
+	)
+	relay.InjectFault(flashbotstest.AnyMethod, flashbotstest.Fault{Message: "internal error"})
+
+	_, err = rpc.FlashbotsSendBundle(key, bundle)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "rate limited")
+	_, err = rpc.FlashbotsSendBundle(key, bundle)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bundle rejected")
+	_, err = rpc.FlashbotsGetUserStats(key, 100)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "internal error")
+
+	// Faults are used up
+	_, err = rpc.FlashbotsSendBundle(key, bundle)
+	require.NoError(t, err)
+	require.Len(t, relay.Bundles(), 1)
+	require.Len(t, relay.Requests(), 4)
+
+	relay.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	res, err := rpc.FlashbotsSendBundle(key, bundle)
+	require.NoError(t, err)
+	require.Equal(t, "0x1234", res.BundleHash)
+}
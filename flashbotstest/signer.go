@@ -0,0 +1,73 @@
+package flashbotstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/metachris/flashbotsrpc"
+)
+
+// SigningService is a mock of the signing service of a flashbotsrpc.RemoteSigner, which signs with the given signers:
+//
+//	service := flashbotstest.NewSigningService(flashbotsrpc.NewPrivateKeySigner(key))
+//	defer service.Close()
+//
+//	signer := flashbotsrpc.NewRemoteSigner(service.URL, crypto.PubkeyToAddress(key.PublicKey))
+//
+// Requests for other addresses are answered with HTTP 404 and the error "unknown key". SigningService is safe for
+// concurrent use.
+type SigningService struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	signers  map[common.Address]flashbotsrpc.Signer
+	requests []flashbotsrpc.RemoteSignRequest
+}
+
+// NewSigningService starts a mock signing service with the keys of the signers. It should be closed with Close.
+func NewSigningService(signers ...flashbotsrpc.Signer) *SigningService {
+	service := &SigningService{
+		signers: make(map[common.Address]flashbotsrpc.Signer),
+	}
+	for _, signer := range signers {
+		service.signers[signer.Address()] = signer
+	}
+	service.Server = httptest.NewServer(http.HandlerFunc(service.handle))
+	return service
+}
+
+// Requests returns all valid requests, including the ones for unknown keys
+func (service *SigningService) Requests() []flashbotsrpc.RemoteSignRequest {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	return append([]flashbotsrpc.RemoteSignRequest{}, service.requests...)
+}
+
+func (service *SigningService) handle(w http.ResponseWriter, r *http.Request) {
+	req := new(flashbotsrpc.RemoteSignRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeJSON(w, http.StatusBadRequest, flashbotsrpc.RemoteSignResponse{Error: err.Error()})
+		return
+	}
+
+	service.lock.Lock()
+	service.requests = append(service.requests, *req)
+	signer, ok := service.signers[req.Address]
+	service.lock.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusNotFound, flashbotsrpc.RemoteSignResponse{Error: "unknown key"})
+		return
+	}
+
+	// The signer is called with the context of the request, so it stops when the client goes away
+	sig, err := signer.SignHashContext(r.Context(), req.Hash)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, flashbotsrpc.RemoteSignResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, flashbotsrpc.RemoteSignResponse{Signature: sig})
+}
@@ -0,0 +1,35 @@
+package flashbotstest_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningService(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	service := flashbotstest.NewSigningService(flashbotsrpc.NewPrivateKeySigner(key))
+	defer service.Close()
+
+	hash := crypto.Keccak256([]byte("body"))
+	sig, err := flashbotsrpc.NewRemoteSigner(service.URL, crypto.PubkeyToAddress(key.PublicKey)).SignHash(hash)
+	require.NoError(t, err)
+	expected, err := crypto.Sign(hash, key)
+	require.NoError(t, err)
+	require.Equal(t, expected, sig)
+
+	// Unknown key
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = flashbotsrpc.NewRemoteSigner(service.URL, crypto.PubkeyToAddress(otherKey.PublicKey)).SignHash(hash)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown key")
+
+	requests := service.Requests()
+	require.Len(t, requests, 2)
+	require.Equal(t, crypto.PubkeyToAddress(otherKey.PublicKey), requests[1].Address)
+}
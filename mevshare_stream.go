@@ -0,0 +1,221 @@
+package flashbotsrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DefaultMevShareStreamURL is the Server-Sent Events endpoint of the Flashbots MEV-Share event stream on mainnet
+const DefaultMevShareStreamURL = "https://mev-share.flashbots.net"
+
+// ErrStreamRejected is returned by Subscribe if the server answers with a 4xx status code other than 429, e.g. for a
+// wrong URL or missing credentials, which reconnecting wouldn't fix
+var ErrStreamRejected = errors.New("mev-share stream rejected the subscription")
+
+// minRetryDelay is the shortest delay before reconnecting, so a retry field of 0 doesn't reconnect in a loop
+const minRetryDelay = 100 * time.Millisecond
+
+// MevShareEvent is a hint about a transaction or bundle on the MEV-Share event stream. Which fields are set depends
+// on the privacy hints the sender chose.
+type MevShareEvent struct {
+	Hash        common.Hash       `json:"hash"`                  // Hash of the transaction or bundle
+	Logs        []MevShareLog     `json:"logs,omitempty"`        // Logs emitted by the transaction or bundle
+	Txs         []MevShareEventTx `json:"txs,omitempty"`         // Transactions of the bundle
+	MevGasPrice *hexutil.Big      `json:"mevGasPrice,omitempty"` // Gas price paid to the builder, as a lower bound
+	GasUsed     *hexutil.Uint64   `json:"gasUsed,omitempty"`     // Gas used by the transaction or bundle, as an upper bound
+}
+
+// MevShareEventTx is a hint about a single transaction of a MevShareEvent
+type MevShareEventTx struct {
+	Hash             *common.Hash    `json:"hash,omitempty"`
+	To               *common.Address `json:"to,omitempty"`
+	FunctionSelector *hexutil.Bytes  `json:"functionSelector,omitempty"` // First 4 bytes of the calldata
+	CallData         *hexutil.Bytes  `json:"callData,omitempty"`
+}
+
+// MevShareStream consumes the MEV-Share event stream, which is served as Server-Sent Events.
+type MevShareStream struct {
+	url        string
+	client     httpClient
+	log        logger
+	Debug      bool
+	Headers    map[string]string // Additional headers to send with the request
+	RetryDelay time.Duration     // Delay before reconnecting, until the server sends the SSE retry field
+}
+
+// NewMevShareStream creates a new MEV-Share event stream consumer for the given SSE endpoint
+func NewMevShareStream(url string, options ...func(stream *MevShareStream)) *MevShareStream {
+	stream := &MevShareStream{
+		url:        url,
+		log:        log.New(os.Stderr, "", log.LstdFlags),
+		Headers:    make(map[string]string),
+		RetryDelay: time.Second,
+	}
+	for _, option := range options {
+		option(stream)
+	}
+	if stream.client == nil {
+		// No timeout, the connection is supposed to stay open
+		stream.client = &http.Client{}
+	}
+	return stream
+}
+
+// Subscribe connects to the event stream and sends all events to the given channel, until the context is done.
+//
+// When the connection drops, it reconnects after RetryDelay, or the delay sent by the server, and asks the server to
+// resume after the last received event with the Last-Event-ID header. Subscribe returns once the context is done, with
+// the context's error, or with ErrStreamRejected if the server rejects the subscription.
+//
+// Concurrent subscriptions of the same MevShareStream each resume after their own last event.
+func (s *MevShareStream) Subscribe(ctx context.Context, events chan<- MevShareEvent) error {
+	var lastEventID string
+	retryDelay := s.RetryDelay
+	for {
+		err := s.subscribe(ctx, events, &lastEventID, &retryDelay)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, ErrStreamRejected) {
+			return err
+		}
+		if retryDelay < minRetryDelay {
+			retryDelay = minRetryDelay
+		}
+		s.debug("mev-share stream: reconnecting in %s: %v", retryDelay, err)
+
+		timer := time.NewTimer(retryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// subscribe reads events from a single connection until it drops. The SSE id field sets lastEventID, which is sent to
+// resume, and the retry field sets retryDelay.
+func (s *MevShareStream) subscribe(ctx context.Context, events chan<- MevShareEvent, lastEventID *string, retryDelay *time.Duration) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Accept", "text/event-stream")
+	req.Header.Add("Cache-Control", "no-cache")
+	if *lastEventID != "" {
+		req.Header.Add("Last-Event-ID", *lastEventID)
+	}
+	for k, v := range s.Headers {
+		req.Header.Add(k, v)
+	}
+
+	response, err := s.client.Do(req)
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return err
+	}
+	if response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: HTTP status code %d", ErrStreamRejected, response.StatusCode)
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP status code %d", response.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024) // calldata can make single lines large
+	var data strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		// An empty line dispatches the event
+		if line == "" {
+			if data.Len() > 0 {
+				if err := s.dispatch(ctx, events, data.String()); err != nil {
+					return err
+				}
+				data.Reset()
+			}
+			continue
+		}
+
+		// Lines starting with a colon are comments, e.g. keepalives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		switch field {
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		case "id":
+			*lastEventID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				*retryDelay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("connection closed")
+}
+
+// dispatch decodes an event and sends it to the channel. Events which can't be decoded are skipped.
+func (s *MevShareStream) dispatch(ctx context.Context, events chan<- MevShareEvent, data string) error {
+	var event MevShareEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		s.debug("mev-share stream: skipping invalid event: %v\n%s", err, data)
+		return nil
+	}
+
+	select {
+	case events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *MevShareStream) debug(format string, v ...interface{}) {
+	if s.Debug {
+		s.log.Println(fmt.Sprintf(format, v...))
+	}
+}
+
+// WithMevShareStreamHttpClient set custom http client for the event stream
+func WithMevShareStreamHttpClient(client httpClient) func(stream *MevShareStream) {
+	return func(stream *MevShareStream) {
+		stream.client = client
+	}
+}
+
+// WithMevShareStreamLogger set custom logger for the event stream
+func WithMevShareStreamLogger(l logger) func(stream *MevShareStream) {
+	return func(stream *MevShareStream) {
+		stream.log = l
+	}
+}
@@ -0,0 +1,136 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// streamConnection is a connection to the test event stream, checked on the test goroutine
+type streamConnection struct {
+	header http.Header
+	at     time.Time
+}
+
+func TestMevShareStream(t *testing.T) {
+	var count int32
+	connections := make(chan streamConnection, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&count, 1)
+		connections <- streamConnection{header: r.Header.Clone(), at: time.Now()}
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		switch n {
+		case 1:
+			// A retry of 0 must not reconnect in a loop
+			fmt.Fprint(w, "retry: 0\n\n")
+			fmt.Fprint(w, ":keepalive\n\n")
+			fmt.Fprint(w, "id: 1\ndata: {\"hash\":\"0x0000000000000000000000000000000000000000000000000000000000000001\",\"logs\":[{\"address\":\"0x0000000000000000000000000000000000000002\",\"topics\":[\"0x0000000000000000000000000000000000000000000000000000000000000003\"],\"data\":\"0x\"}]}\n\n")
+			fmt.Fprint(w, "id: 2\ndata: invalid\n\n")
+			// Data split over two lines, with CRLF line endings
+			fmt.Fprint(w, "id: 3\r\ndata: {\"hash\":\"0x0000000000000000000000000000000000000000000000000000000000000004\",\r\ndata: \"txs\":[{\"to\":\"0x0000000000000000000000000000000000000005\",\"functionSelector\":\"0xa9059cbb\",\"callData\":\"0xa9059cbb00\"}]}\r\n\r\n")
+			// Connection drops
+		case 2:
+			fmt.Fprint(w, "id: 4\ndata: {\"hash\":\"0x0000000000000000000000000000000000000000000000000000000000000006\",\"mevGasPrice\":\"0x3b9aca00\",\"gasUsed\":\"0x5208\"}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	stream := NewMevShareStream(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan MevShareEvent)
+	done := make(chan error)
+	go func() {
+		done <- stream.Subscribe(ctx, events)
+	}()
+
+	first := <-connections
+	require.Equal(t, "text/event-stream", first.header.Get("Accept"))
+	require.Empty(t, first.header.Get("Last-Event-ID"))
+
+	event := <-events
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000001", event.Hash.Hex())
+	require.Len(t, event.Logs, 1)
+	require.Equal(t, "0x0000000000000000000000000000000000000002", event.Logs[0].Address.Hex())
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000003", event.Logs[0].Topics[0].Hex())
+
+	event = <-events
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000004", event.Hash.Hex())
+	require.Len(t, event.Txs, 1)
+	require.Equal(t, "0x0000000000000000000000000000000000000005", event.Txs[0].To.Hex())
+	require.Equal(t, "0xa9059cbb", event.Txs[0].FunctionSelector.String())
+	require.Equal(t, "0xa9059cbb00", event.Txs[0].CallData.String())
+
+	event = <-events
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000006", event.Hash.Hex())
+	require.Equal(t, "1000000000", event.MevGasPrice.ToInt().String())
+	require.Equal(t, uint64(21000), uint64(*event.GasUsed))
+
+	second := <-connections
+	require.Equal(t, "3", second.header.Get("Last-Event-ID"))
+	require.GreaterOrEqual(t, second.at.Sub(first.at), minRetryDelay)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Equal(t, int32(2), atomic.LoadInt32(&count))
+}
+
+func TestMevShareStreamRejected(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := NewMevShareStream(server.URL).Subscribe(ctx, make(chan MevShareEvent))
+	require.ErrorIs(t, err, ErrStreamRejected)
+	require.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
+
+func TestMevShareStreamConcurrent(t *testing.T) {
+	// Every first connection gets its own event ID, which the reconnect has to resume from
+	var count int32
+	lastEventIDs := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		lastEventID := r.Header.Get("Last-Event-ID")
+		if lastEventID == "" {
+			fmt.Fprintf(w, "retry: 0\nid: %d\n\n", atomic.AddInt32(&count, 1))
+			return
+		}
+		lastEventIDs <- lastEventID
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	stream := NewMevShareStream(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			done <- stream.Subscribe(ctx, make(chan MevShareEvent))
+		}()
+	}
+
+	ids := []string{<-lastEventIDs, <-lastEventIDs}
+	require.ElementsMatch(t, []string{"1", "2"}, ids)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.ErrorIs(t, <-done, context.Canceled)
+}
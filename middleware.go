@@ -0,0 +1,86 @@
+package flashbotsrpc
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+)
+
+// RoundTripRequest is a JSON-RPC request as seen by middleware, before it is sent. The Header already contains the
+// X-Flashbots-Signature of signed requests and the Headers of the client. Middleware which changes the Body of a signed
+// request has to update the signature with Resign.
+type RoundTripRequest struct {
+	Method string // Method of the request, comma separated for batches
+	URL    string
+	Header http.Header
+	Body   []byte
+
+	// Resign returns the X-Flashbots-Signature header value for a body, signed by the signer of the request. It is nil
+	// for requests without signature.
+	Resign func(body []byte) (string, error)
+}
+
+// RoundTripResponse is the HTTP response to a RoundTripRequest, with the complete body
+type RoundTripResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// RoundTripFunc sends a request and returns its response. The response may be returned together with an error, if its
+// body can't be read.
+type RoundTripFunc func(ctx context.Context, req *RoundTripRequest) (*RoundTripResponse, error)
+
+// Middleware wraps the sending of requests, e.g. to add headers, change or record requests and responses, collect metrics
+// or inject faults. It is called for every attempt, so retries (see RetryPolicy) pass through it again. Middleware can
+// answer a request without calling next.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// chainMiddleware wraps transport with the middleware, the first middleware is the outermost
+func chainMiddleware(middleware []Middleware, transport RoundTripFunc) RoundTripFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		transport = middleware[i](transport)
+	}
+	return transport
+}
+
+// newRoundTripRequest returns a JSON-RPC request with the default headers, and the signature if it isn't empty. signer
+// is the signer of the signature, or nil.
+func newRoundTripRequest(ctx context.Context, method, url string, body []byte, signature string, signer Signer) *RoundTripRequest {
+	req := &RoundTripRequest{Method: method, URL: url, Header: make(http.Header), Body: body}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	if signature != "" {
+		req.Header.Add("X-Flashbots-Signature", signature)
+	}
+	if signer != nil {
+		req.Resign = func(body []byte) (string, error) {
+			return flashbotsSignature(ctx, body, signer)
+		}
+	}
+	return req
+}
+
+// httpRoundTrip returns the transport at the end of a middleware chain, which sends requests with client and reads
+// response bodies up to maxResponseSize
+func httpRoundTrip(client httpClient, maxResponseSize int64) RoundTripFunc {
+	return func(ctx context.Context, req *RoundTripRequest) (*RoundTripResponse, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", req.URL, bytes.NewReader(req.Body))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header = req.Header
+
+		response, err := client.Do(httpReq)
+		if response != nil {
+			defer response.Body.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// The status code is also returned if the body can't be read
+		data, err := readBody(response.Body, maxResponseSize)
+		return &RoundTripResponse{StatusCode: response.StatusCode, Header: response.Header, Body: data}, err
+	}
+}
@@ -0,0 +1,205 @@
+package flashbotsrpc_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	server := flashbotstest.NewNode()
+	defer server.Close()
+	server.SetResult("eth_blockNumber", "0x64")
+
+	var calls []string
+	trace := func(name string) flashbotsrpc.Middleware {
+		return func(next flashbotsrpc.RoundTripFunc) flashbotsrpc.RoundTripFunc {
+			return func(ctx context.Context, req *flashbotsrpc.RoundTripRequest) (*flashbotsrpc.RoundTripResponse, error) {
+				calls = append(calls, name+" "+req.Method)
+				res, err := next(ctx, req)
+				calls = append(calls, name+" done")
+				return res, err
+			}
+		}
+	}
+
+	rpc := flashbotsrpc.New(server.URL, flashbotsrpc.WithMiddleware(trace("a")), flashbotsrpc.WithMiddleware(trace("b")))
+	blockNumber, err := rpc.EthBlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, 100, blockNumber)
+	require.Equal(t, []string{"a eth_blockNumber", "b eth_blockNumber", "b done", "a done"}, calls)
+}
+
+func TestMiddlewareHeaders(t *testing.T) {
+	server := flashbotstest.NewNode()
+	defer server.Close()
+	server.SetResult(flashbotstest.AnyMethod, "0x64")
+
+	auth := func(next flashbotsrpc.RoundTripFunc) flashbotsrpc.RoundTripFunc {
+		return func(ctx context.Context, req *flashbotsrpc.RoundTripRequest) (*flashbotsrpc.RoundTripResponse, error) {
+			if req.Method == "eth_sendRawTransaction" {
+				req.Header.Set("Authorization", "Bearer secret")
+			}
+			return next(ctx, req)
+		}
+	}
+	rpc := flashbotsrpc.New(server.URL, flashbotsrpc.WithMiddleware(auth))
+	rpc.Headers["X-Client"] = "test"
+
+	_, err := rpc.EthBlockNumber()
+	require.NoError(t, err)
+	_, err = rpc.EthSendRawTransaction("0x01")
+	require.NoError(t, err)
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	require.Empty(t, requests[0].Header.Get("Authorization"))
+	require.Equal(t, "test", requests[0].Header.Get("X-Client"))
+	require.Equal(t, "Bearer secret", requests[1].Header.Get("Authorization"))
+	require.Equal(t, "application/json", requests[1].Header.Get("Content-Type"))
+}
+
+func TestMiddlewareResign(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	// The relay verifies the signature, so the mutated body has to be signed again
+	server := flashbotstest.NewNode()
+	defer server.Close()
+	server.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+
+	var body []byte
+	mutate := func(next flashbotsrpc.RoundTripFunc) flashbotsrpc.RoundTripFunc {
+		return func(ctx context.Context, req *flashbotsrpc.RoundTripRequest) (*flashbotsrpc.RoundTripResponse, error) {
+			req.Body = bytes.Replace(req.Body, []byte(`"0x01"`), []byte(`"0x02"`), 1)
+			signature, err := req.Resign(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("X-Flashbots-Signature", signature)
+			body = req.Body
+			return next(ctx, req)
+		}
+	}
+
+	rpc := flashbotsrpc.New(server.URL, flashbotsrpc.WithMiddleware(mutate))
+	res, err := rpc.FlashbotsSendBundle(key, flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.NoError(t, err)
+	require.Equal(t, "0x1234", res.BundleHash)
+	require.Contains(t, string(body), `"0x02"`)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), server.Requests()[0].Signer)
+
+	// Requests without signature can't be signed again
+	var resign func([]byte) (string, error)
+	record := func(next flashbotsrpc.RoundTripFunc) flashbotsrpc.RoundTripFunc {
+		return func(ctx context.Context, req *flashbotsrpc.RoundTripRequest) (*flashbotsrpc.RoundTripResponse, error) {
+			resign = req.Resign
+			return next(ctx, req)
+		}
+	}
+	_, _ = flashbotsrpc.New(server.URL, flashbotsrpc.WithMiddleware(record)).EthBlockNumber()
+	require.Nil(t, resign)
+}
+
+func TestMiddlewareRecording(t *testing.T) {
+	server := flashbotstest.NewNode()
+	defer server.Close()
+	server.SetResult("eth_blockNumber", "0x64")
+
+	var requests, responses []string
+	record := func(next flashbotsrpc.RoundTripFunc) flashbotsrpc.RoundTripFunc {
+		return func(ctx context.Context, req *flashbotsrpc.RoundTripRequest) (*flashbotsrpc.RoundTripResponse, error) {
+			requests = append(requests, string(req.Body))
+			res, err := next(ctx, req)
+			if res != nil {
+				responses = append(responses, string(res.Body))
+			}
+			return res, err
+		}
+	}
+
+	_, err := flashbotsrpc.New(server.URL, flashbotsrpc.WithMiddleware(record)).EthBlockNumber()
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	require.Contains(t, requests[0], `"method":"eth_blockNumber"`)
+	require.Len(t, responses, 1)
+	require.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":"0x64"}`, responses[0])
+}
+
+func TestMiddlewareFaultInjection(t *testing.T) {
+	server := flashbotstest.NewNode()
+	defer server.Close()
+	server.SetResult("eth_blockNumber", "0x64")
+
+	// The first attempt fails without reaching the server, the retry passes through the middleware again
+	var faults int32
+	inject := func(next flashbotsrpc.RoundTripFunc) flashbotsrpc.RoundTripFunc {
+		return func(ctx context.Context, req *flashbotsrpc.RoundTripRequest) (*flashbotsrpc.RoundTripResponse, error) {
+			if atomic.AddInt32(&faults, 1) == 1 {
+				return &flashbotsrpc.RoundTripResponse{StatusCode: http.StatusServiceUnavailable, Header: make(http.Header)}, nil
+			}
+			return next(ctx, req)
+		}
+	}
+
+	rpc := flashbotsrpc.New(server.URL, flashbotsrpc.WithRetryPolicy(testRetryPolicy), flashbotsrpc.WithMiddleware(inject))
+	blockNumber, err := rpc.EthBlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, 100, blockNumber)
+	require.Equal(t, int32(2), atomic.LoadInt32(&faults))
+	require.Len(t, server.Requests(), 1)
+
+	// Errors of middleware are returned
+	errFault := errors.New("injected fault")
+	fail := func(next flashbotsrpc.RoundTripFunc) flashbotsrpc.RoundTripFunc {
+		return func(ctx context.Context, req *flashbotsrpc.RoundTripRequest) (*flashbotsrpc.RoundTripResponse, error) {
+			return nil, errFault
+		}
+	}
+	_, err = flashbotsrpc.New(server.URL, flashbotsrpc.WithMiddleware(fail)).EthBlockNumber()
+	require.ErrorIs(t, err, errFault)
+	require.Len(t, server.Requests(), 1)
+}
+
+func TestBroadcastMiddleware(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	server1 := flashbotstest.NewNode()
+	defer server1.Close()
+	server1.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	server2 := flashbotstest.NewNode()
+	defer server2.Close()
+	server2.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+
+	// Skip the second builder
+	var lock sync.Mutex
+	urls := make(map[string]string)
+	skip := func(next flashbotsrpc.RoundTripFunc) flashbotsrpc.RoundTripFunc {
+		return func(ctx context.Context, req *flashbotsrpc.RoundTripRequest) (*flashbotsrpc.RoundTripResponse, error) {
+			lock.Lock()
+			urls[req.URL] = req.Method
+			lock.Unlock()
+			if req.URL == server2.URL {
+				return nil, errors.New("skipped")
+			}
+			return next(ctx, req)
+		}
+	}
+
+	broadcaster := flashbotsrpc.NewBuilderBroadcastRPC([]string{server1.URL, server2.URL}, flashbotsrpc.WithBroadcastMiddleware(skip))
+	results := broadcaster.BroadcastBundle(key, flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.Len(t, results, 2)
+	require.NoError(t, results[0].Err)
+	require.EqualError(t, results[1].Err, "skipped")
+	require.Equal(t, map[string]string{server1.URL: "eth_sendBundle", server2.URL: "eth_sendBundle"}, urls)
+}
@@ -34,3 +34,24 @@ func WithDebug(enabled bool) func(rpc *FlashbotsRPC) {
 		rpc.Debug = enabled
 	}
 }
+
+// WithRetryPolicy set the retry policy for failed requests, see RetryPolicy
+func WithRetryPolicy(policy RetryPolicy) func(rpc *FlashbotsRPC) {
+	return func(rpc *FlashbotsRPC) {
+		rpc.Retry = policy
+	}
+}
+
+// WithRateLimiter limit the requests to the url, see RateLimiter
+func WithRateLimiter(limiter *RateLimiter) func(rpc *FlashbotsRPC) {
+	return func(rpc *FlashbotsRPC) {
+		rpc.RateLimiter = limiter
+	}
+}
+
+// WithMiddleware add middleware for all requests, see Middleware. The first middleware is the outermost.
+func WithMiddleware(middleware ...Middleware) func(rpc *FlashbotsRPC) {
+	return func(rpc *FlashbotsRPC) {
+		rpc.middleware = append(rpc.middleware, middleware...)
+	}
+}
@@ -0,0 +1,279 @@
+package flashbotsrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// JSON-RPC error codes of ProxyHandler responses
+const (
+	proxyCodeParseError     = -32700
+	proxyCodeInvalidRequest = -32600
+	proxyCodeMethodNotFound = -32601
+	proxyCodeInvalidParams  = -32602
+	proxyCodeServerError    = -32000
+)
+
+// ProxyHandler is an http.Handler which accepts unsigned JSON-RPC requests, e.g. on localhost for tooling that can't sign
+// requests itself. Flashbots methods (eth_sendBundle, eth_callBundle, eth_cancelBundle, eth_sendPrivateTransaction,
+// eth_cancelPrivateTransaction, flashbots_* and mev_*) are signed with the signer of the handler and sent to the relay, or
+// broadcast to builders. All other methods are forwarded to an Ethereum node, if one is set. Batch requests are supported.
+//
+// Only requests with Content-Type application/json are accepted. Requests from browsers (with an Origin header) are
+// rejected, unless the origin is allowed with WithProxyAllowedOrigins, so websites can't use the signer of the proxy.
+type ProxyHandler struct {
+	relay          *FlashbotsRPC
+	broadcaster    *BuilderBroadcastRPC
+	node           *FlashbotsRPC
+	signer         Signer
+	log            logger
+	allowedOrigins []string
+	Debug          bool
+	MaxBodySize    int64 // Larger requests are rejected with HTTP 413
+}
+
+// NewProxyHandler creates a proxy which signs Flashbots methods with signer and sends them to relay. relay may be nil
+// if the requests are broadcast to builders (see WithProxyBroadcaster).
+func NewProxyHandler(relay *FlashbotsRPC, signer Signer, options ...func(proxy *ProxyHandler)) *ProxyHandler {
+	proxy := &ProxyHandler{
+		relay:       relay,
+		signer:      signer,
+		log:         log.New(os.Stderr, "", log.LstdFlags),
+		MaxBodySize: DefaultMaxSignedBodySize,
+	}
+	for _, option := range options {
+		option(proxy)
+	}
+	return proxy
+}
+
+// proxyRequest is a JSON-RPC request received by the proxy. The ID is passed back as is.
+type proxyRequest struct {
+	ID      json.RawMessage   `json:"id"`
+	JSONRPC string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+// params returns the params for forwarding the request
+func (request proxyRequest) params() []interface{} {
+	params := make([]interface{}, len(request.Params))
+	for i, param := range request.Params {
+		params[i] = param
+	}
+	return params
+}
+
+type proxyResponse struct {
+	ID      json.RawMessage `json:"id"`
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RpcError       `json:"error,omitempty"`
+}
+
+func (proxy *ProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveJSONRPC(w, r, proxy.MaxBodySize, proxy.allowedOrigins, proxy.handle)
+}
+
+// serveJSONRPC reads a single or batch JSON-RPC request, and writes the responses returned by handle.
+//
+// Any website can make a browser send a POST request with Content-Type text/plain to a local server without a CORS
+// preflight, so requests with another Content-Type than application/json are rejected with HTTP 415, and requests with an
+// Origin header which isn't in allowedOrigins with HTTP 403. Allowed origins get the CORS headers, also for preflights.
+func serveJSONRPC(w http.ResponseWriter, r *http.Request, maxBodySize int64, allowedOrigins []string, handle func(ctx context.Context, request proxyRequest) proxyResponse) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if !isAllowedOrigin(allowedOrigins, origin) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", http.MethodPost)
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var requests []proxyRequest
+		if err := json.Unmarshal(body, &requests); err != nil {
+			writeJSONRPCResponse(w, proxyErrorResponse(nil, proxyCodeParseError, err.Error()))
+			return
+		}
+		if len(requests) == 0 {
+			writeJSONRPCResponse(w, proxyErrorResponse(nil, proxyCodeInvalidRequest, "empty batch"))
+			return
+		}
+
+		responses := make([]proxyResponse, len(requests))
+		for i, request := range requests {
+			responses[i] = handle(r.Context(), request)
+		}
+		writeJSONRPCResponse(w, responses)
+		return
+	}
+
+	var request proxyRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeJSONRPCResponse(w, proxyErrorResponse(nil, proxyCodeParseError, err.Error()))
+		return
+	}
+	writeJSONRPCResponse(w, handle(r.Context(), request))
+}
+
+// handle forwards a single request and returns the response for the client
+func (proxy *ProxyHandler) handle(ctx context.Context, request proxyRequest) proxyResponse {
+	if request.Method == "" {
+		return proxyErrorResponse(request.ID, proxyCodeInvalidRequest, "missing method")
+	}
+
+	params := request.params()
+
+	var result json.RawMessage
+	var err error
+	switch {
+	case isFlashbotsMethod(request.Method) && proxy.broadcaster != nil:
+		result, err = proxy.broadcast(ctx, request.Method, params)
+	case isFlashbotsMethod(request.Method) && proxy.relay != nil:
+		result, err = proxy.relay.CallWithFlashbotsSignatureContext(ctx, request.Method, proxy.signer, params...)
+	case !isFlashbotsMethod(request.Method) && proxy.node != nil:
+		result, err = proxy.node.CallContext(ctx, request.Method, params...)
+	default:
+		return proxyErrorResponse(request.ID, proxyCodeMethodNotFound, fmt.Sprintf("the method %s does not exist/is not available", request.Method))
+	}
+
+	if proxy.Debug {
+		proxy.log.Println(fmt.Sprintf("proxy: %s: result: %s, error: %v", request.Method, result, err))
+	}
+	return proxyResultResponse(request.ID, result, err)
+}
+
+// broadcast sends the request to all builders and returns the result of the first builder which accepted it,
+// in the order of the builders, or the error of the first builder if none did
+func (proxy *ProxyHandler) broadcast(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	responses := proxy.broadcaster.broadcastRequest(ctx, method, proxy.signer, params...)
+	results := make([]BuilderBroadcastResult, len(responses))
+	for i, response := range responses {
+		results[i] = response.BuilderBroadcastResult
+	}
+
+	i, err := firstSuccess(results)
+	if err != nil {
+		return nil, err
+	}
+	return responses[i].Msg, nil
+}
+
+// isAllowedOrigin returns whether origin is one of allowedOrigins, or allowedOrigins contains "*"
+func isAllowedOrigin(allowedOrigins []string, origin string) bool {
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSONRPCResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// proxyResultResponse returns the response for the result or error of a forwarded request. JSON-RPC errors of nodes,
+// relays and builders are passed on.
+func proxyResultResponse(id json.RawMessage, result json.RawMessage, err error) proxyResponse {
+	if err != nil {
+		var rpcErr RpcError
+		if errors.As(err, &rpcErr) {
+			response := proxyErrorResponse(id, rpcErr.Code, rpcErr.Message)
+			response.Error.Data = rpcErr.Data
+			return response
+		}
+		var relayErr *RelayError
+		if errors.As(err, &relayErr) && relayErr.Code != 0 {
+			response := proxyErrorResponse(id, relayErr.Code, relayErr.Message)
+			response.Error.Data = relayErr.Data
+			return response
+		}
+		return proxyErrorResponse(id, proxyCodeServerError, err.Error())
+	}
+
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	return proxyResponse{ID: id, JSONRPC: "2.0", Result: result}
+}
+
+func proxyErrorResponse(id json.RawMessage, code int, message string) proxyResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return proxyResponse{ID: id, JSONRPC: "2.0", Error: &RpcError{Code: code, Message: message}}
+}
+
+// isFlashbotsMethod returns whether a method must be signed and sent to the relay instead of a node
+func isFlashbotsMethod(method string) bool {
+	switch method {
+	case "eth_sendBundle", "eth_callBundle", "eth_cancelBundle", "eth_sendPrivateTransaction", "eth_cancelPrivateTransaction":
+		return true
+	}
+	return strings.HasPrefix(method, "flashbots_") || strings.HasPrefix(method, "mev_")
+}
+
+// WithProxyBroadcaster broadcasts Flashbots methods to builders instead of sending them to the relay. The proxy responds
+// with the result of the first builder which accepted the request.
+func WithProxyBroadcaster(broadcaster *BuilderBroadcastRPC) func(proxy *ProxyHandler) {
+	return func(proxy *ProxyHandler) {
+		proxy.broadcaster = broadcaster
+	}
+}
+
+// WithProxyNode forwards all methods which aren't Flashbots methods to an Ethereum node, unsigned
+func WithProxyNode(node *FlashbotsRPC) func(proxy *ProxyHandler) {
+	return func(proxy *ProxyHandler) {
+		proxy.node = node
+	}
+}
+
+// WithProxyAllowedOrigins accepts requests from browsers with one of the given origins (e.g. "https://app.example.com",
+// or "*" for all), which are rejected by default
+func WithProxyAllowedOrigins(origins ...string) func(proxy *ProxyHandler) {
+	return func(proxy *ProxyHandler) {
+		proxy.allowedOrigins = append(proxy.allowedOrigins, origins...)
+	}
+}
+
+// WithProxyLogger set custom logger for the proxy
+func WithProxyLogger(l logger) func(proxy *ProxyHandler) {
+	return func(proxy *ProxyHandler) {
+		proxy.log = l
+	}
+}
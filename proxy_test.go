@@ -0,0 +1,188 @@
+package flashbotsrpc_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func postProxy(t *testing.T, url, body string) string {
+	res, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestProxyHandler(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := flashbotsrpc.NewPrivateKeySigner(key)
+
+	relay := flashbotstest.NewNode()
+	defer relay.Close()
+	relay.SetResult(flashbotstest.AnyMethod, flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult(flashbotstest.AnyMethod, "0x64")
+
+	proxy := httptest.NewServer(flashbotsrpc.NewProxyHandler(flashbotsrpc.NewFlashbotsRPC(relay.URL), signer, flashbotsrpc.WithProxyNode(flashbotsrpc.NewFlashbotsRPC(node.URL))))
+	defer proxy.Close()
+
+	// Flashbots methods are signed and sent to the relay, and the ID of the request is kept
+	res := postProxy(t, proxy.URL, `{"jsonrpc":"2.0","id":"abc","method":"eth_sendBundle","params":[{"txs":["0x01"],"blockNumber":"0x1"}]}`)
+	require.Equal(t, "abc", gjson.Get(res, "id").String())
+	require.Equal(t, "0x1234", gjson.Get(res, "result.bundleHash").String())
+
+	// Other methods go to the node
+	res = postProxy(t, proxy.URL, `{"jsonrpc":"2.0","id":7,"method":"eth_blockNumber","params":[]}`)
+	require.Equal(t, int64(7), gjson.Get(res, "id").Int())
+	require.Equal(t, "0x64", gjson.Get(res, "result").String())
+
+	res = postProxy(t, proxy.URL, `[
+		{"jsonrpc":"2.0","id":1,"method":"flashbots_getUserStats","params":["0x1"]},
+		{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber","params":[]}
+	]`)
+	require.Equal(t, int64(1), gjson.Get(res, "0.id").Int())
+	require.Equal(t, "0x1234", gjson.Get(res, "0.result.bundleHash").String())
+	require.Equal(t, int64(2), gjson.Get(res, "1.id").Int())
+	require.Equal(t, "0x64", gjson.Get(res, "1.result").String())
+
+	res = postProxy(t, proxy.URL, `{"jsonrpc":"2.0","id":1,"method":`)
+	require.Equal(t, int64(-32700), gjson.Get(res, "error.code").Int())
+
+	requests := relay.Requests()
+	require.Len(t, requests, 2)
+	for _, request := range requests {
+		require.Equal(t, signer.Address(), request.Signer)
+	}
+}
+
+func TestProxyHandlerErrors(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	relay := flashbotstest.NewNode()
+	defer relay.Close()
+	relay.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadRequest, Message: "block param must be a hex int"})
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{Code: flashbotstest.CodeInvalidParams, Message: "invalid argument"})
+
+	proxy := httptest.NewServer(flashbotsrpc.NewProxyHandler(flashbotsrpc.NewFlashbotsRPC(relay.URL), flashbotsrpc.NewPrivateKeySigner(key), flashbotsrpc.WithProxyNode(flashbotsrpc.NewFlashbotsRPC(node.URL))))
+	defer proxy.Close()
+
+	res := postProxy(t, proxy.URL, `{"jsonrpc":"2.0","id":1,"method":"eth_sendBundle","params":[{}]}`)
+	require.Equal(t, int64(flashbotstest.CodeServerError), gjson.Get(res, "error.code").Int())
+	require.Contains(t, gjson.Get(res, "error.message").String(), "block param must be a hex int")
+
+	// JSON-RPC errors of the node are passed on
+	res = postProxy(t, proxy.URL, `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x1"]}`)
+	require.Equal(t, int64(-32602), gjson.Get(res, "error.code").Int())
+	require.Equal(t, "invalid argument", gjson.Get(res, "error.message").String())
+
+	// Without a node, only Flashbots methods are available
+	proxy = httptest.NewServer(flashbotsrpc.NewProxyHandler(flashbotsrpc.NewFlashbotsRPC(relay.URL), flashbotsrpc.NewPrivateKeySigner(key)))
+	defer proxy.Close()
+	res = postProxy(t, proxy.URL, `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	require.Equal(t, int64(flashbotstest.CodeMethodNotFound), gjson.Get(res, "error.code").Int())
+}
+
+func TestProxyHandlerBroadcast(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := flashbotsrpc.NewPrivateKeySigner(key)
+
+	failing := flashbotstest.NewNode()
+	defer failing.Close()
+	failing.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{Code: flashbotstest.CodeServerError, Message: "bundle rejected"})
+	accepting := flashbotstest.NewNode()
+	defer accepting.Close()
+	accepting.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x5678"})
+
+	broadcaster := flashbotsrpc.NewBuilderBroadcastRPC([]string{failing.URL, accepting.URL})
+	proxy := httptest.NewServer(flashbotsrpc.NewProxyHandler(nil, signer, flashbotsrpc.WithProxyBroadcaster(broadcaster)))
+	defer proxy.Close()
+
+	res := postProxy(t, proxy.URL, `{"jsonrpc":"2.0","id":1,"method":"eth_sendBundle","params":[{"txs":["0x01"],"blockNumber":"0x1"}]}`)
+	require.Equal(t, "0x5678", gjson.Get(res, "result.bundleHash").String())
+	require.Equal(t, signer.Address(), accepting.Requests()[0].Signer)
+
+	broadcaster = flashbotsrpc.NewBuilderBroadcastRPC([]string{failing.URL})
+	proxy = httptest.NewServer(flashbotsrpc.NewProxyHandler(nil, signer, flashbotsrpc.WithProxyBroadcaster(broadcaster)))
+	defer proxy.Close()
+
+	res = postProxy(t, proxy.URL, `{"jsonrpc":"2.0","id":1,"method":"eth_sendBundle","params":[{"txs":["0x01"],"blockNumber":"0x1"}]}`)
+	require.Contains(t, gjson.Get(res, "error.message").String(), "bundle rejected")
+}
+
+func TestProxyHandlerBrowserRequests(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := flashbotsrpc.NewPrivateKeySigner(key)
+
+	relay := flashbotstest.NewNode()
+	defer relay.Close()
+	relay.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	rpc := flashbotsrpc.New(relay.URL)
+
+	bundle := `{"jsonrpc":"2.0","id":1,"method":"eth_sendBundle","params":[{"txs":["0x01"],"blockNumber":"0x1"}]}`
+	post := func(url, contentType, origin string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(bundle))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		return res
+	}
+
+	proxy := httptest.NewServer(flashbotsrpc.NewProxyHandler(rpc, signer))
+	defer proxy.Close()
+
+	// A website can send a "simple" cross-origin request with text/plain without preflight
+	res := post(proxy.URL, "text/plain", "")
+	require.Equal(t, http.StatusUnsupportedMediaType, res.StatusCode)
+	res = post(proxy.URL, "application/json", "https://evil.example.com")
+	require.Equal(t, http.StatusForbidden, res.StatusCode)
+	require.Empty(t, relay.Requests())
+
+	res = post(proxy.URL, "application/json; charset=utf-8", "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Len(t, relay.Requests(), 1)
+
+	// Allowed origins get the CORS headers
+	proxy = httptest.NewServer(flashbotsrpc.NewProxyHandler(rpc, signer, flashbotsrpc.WithProxyAllowedOrigins("https://app.example.com")))
+	defer proxy.Close()
+
+	req, err := http.NewRequest(http.MethodOptions, proxy.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://app.example.com")
+	preflight, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	preflight.Body.Close()
+	require.Equal(t, http.StatusNoContent, preflight.StatusCode)
+	require.Equal(t, "https://app.example.com", preflight.Header.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "Content-Type", preflight.Header.Get("Access-Control-Allow-Headers"))
+
+	res = post(proxy.URL, "application/json", "https://app.example.com")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "https://app.example.com", res.Header.Get("Access-Control-Allow-Origin"))
+	res = post(proxy.URL, "application/json", "https://evil.example.com")
+	require.Equal(t, http.StatusForbidden, res.StatusCode)
+	require.Len(t, relay.Requests(), 2)
+}
@@ -0,0 +1,153 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRateLimited is returned instead of sending a request if a RateLimiter with FailFast has no token left. Rate limits
+// of the relay itself are ErrRelayRateLimited.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimit configures a token bucket, which holds up to Burst tokens and is refilled with Rate tokens per second.
+// Each request takes a token. A Rate of 0 means no limit.
+type RateLimit struct {
+	Rate  float64 // Requests per second
+	Burst int     // Requests which can be sent at once, at least 1
+}
+
+// RateLimiter limits the requests to an endpoint with token buckets: one for all requests to the endpoint, and one per
+// method with its own limit. A request takes a token from the endpoint bucket and from the bucket of its method. By
+// default it waits until the tokens are available, with FailFast it returns ErrRateLimited instead.
+//
+// A RateLimiter is safe for concurrent use. It should only be shared between clients of the same endpoint, every
+// builder of a BuilderBroadcastRPC needs its own (see BuilderEndpoint.RateLimiter and WithBuilderRateLimit).
+type RateLimiter struct {
+	lock     sync.Mutex
+	endpoint *tokenBucket
+	methods  map[string]*tokenBucket
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+
+	FailFast bool // Return ErrRateLimited instead of waiting for a token
+}
+
+// NewRateLimiter creates a rate limiter for all requests to an endpoint. Methods with their own limits are added with
+// WithMethodRateLimit.
+func NewRateLimiter(limit RateLimit, options ...func(limiter *RateLimiter)) *RateLimiter {
+	limiter := &RateLimiter{
+		methods: make(map[string]*tokenBucket),
+		now:     time.Now,
+		sleep:   sleepContext,
+	}
+	limiter.endpoint = newTokenBucket(limit)
+	for _, option := range options {
+		option(limiter)
+	}
+	return limiter
+}
+
+// WithMethodRateLimit limits the requests of a method, in addition to the limit of the endpoint
+func WithMethodRateLimit(method string, limit RateLimit) func(limiter *RateLimiter) {
+	return func(limiter *RateLimiter) {
+		limiter.methods[method] = newTokenBucket(limit)
+	}
+}
+
+// Wait takes the tokens for a request with the given methods (more than one for batches): one from the endpoint bucket,
+// and one per method from the bucket of the method. It blocks until the tokens are available or the context is done.
+// With FailFast, it returns ErrRateLimited if the tokens aren't available right away and takes none.
+func (limiter *RateLimiter) Wait(ctx context.Context, methods ...string) error {
+	limiter.lock.Lock()
+	now := limiter.now()
+	buckets := limiter.buckets(methods)
+
+	var delay time.Duration
+	for bucket, n := range buckets {
+		bucket.refill(now)
+		if d := bucket.delay(n); d > delay {
+			delay = d
+		}
+	}
+	if delay > 0 && limiter.FailFast {
+		limiter.lock.Unlock()
+		return fmt.Errorf("%w: %v, next request possible in %s", ErrRateLimited, methods, delay)
+	}
+
+	// Tokens are reserved before waiting, so concurrent requests queue up behind each other
+	for bucket, n := range buckets {
+		bucket.tokens -= n
+	}
+	limiter.lock.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	if err := limiter.sleep(ctx, delay); err != nil {
+		limiter.lock.Lock()
+		for bucket, n := range buckets {
+			bucket.tokens += n
+		}
+		limiter.lock.Unlock()
+		return err
+	}
+	return nil
+}
+
+// buckets returns the buckets a request with the given methods takes tokens from, and the number of tokens.
+// The lock must be held.
+func (limiter *RateLimiter) buckets(methods []string) map[*tokenBucket]float64 {
+	buckets := make(map[*tokenBucket]float64)
+	if limiter.endpoint != nil {
+		buckets[limiter.endpoint] = 1
+	}
+	for _, method := range methods {
+		if bucket := limiter.methods[method]; bucket != nil {
+			buckets[bucket]++
+		}
+	}
+	return buckets
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64   // Negative if requests are waiting for tokens
+	last   time.Time // Time of the last refill, zero before the first request
+}
+
+// newTokenBucket returns a full bucket, or nil if the limit has no rate
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &tokenBucket{limit: limit, tokens: float64(limit.Burst)}
+}
+
+// refill adds the tokens since the last refill, up to Burst
+func (bucket *tokenBucket) refill(now time.Time) {
+	if bucket.last.IsZero() {
+		bucket.last = now
+	}
+	if now.After(bucket.last) {
+		bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.limit.Rate
+		bucket.last = now
+	}
+	if bucket.tokens > float64(bucket.limit.Burst) {
+		bucket.tokens = float64(bucket.limit.Burst)
+	}
+}
+
+// delay returns how long it takes until the bucket has n tokens
+func (bucket *tokenBucket) delay(n float64) time.Duration {
+	if bucket.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - bucket.tokens) / bucket.limit.Rate * float64(time.Second))
+}
@@ -0,0 +1,133 @@
+package flashbotsrpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterFailFast(t *testing.T) {
+	now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	withClock := flashbotsrpc.WithRateLimiterClock(clock, nil)
+
+	limiter := flashbotsrpc.NewRateLimiter(flashbotsrpc.RateLimit{Rate: 1, Burst: 2}, withClock, flashbotsrpc.WithMethodRateLimit("eth_sendBundle", flashbotsrpc.RateLimit{Rate: 0.1}))
+	limiter.FailFast = true
+	ctx := context.Background()
+
+	require.NoError(t, limiter.Wait(ctx, "eth_sendBundle"))
+	// The method has no token left, and the request takes none from the endpoint
+	require.ErrorIs(t, limiter.Wait(ctx, "eth_sendBundle"), flashbotsrpc.ErrRateLimited)
+	require.NoError(t, limiter.Wait(ctx, "eth_call"))
+	require.ErrorIs(t, limiter.Wait(ctx, "eth_call"), flashbotsrpc.ErrRateLimited)
+
+	now = now.Add(time.Second)
+	require.NoError(t, limiter.Wait(ctx, "eth_call"))
+	require.ErrorIs(t, limiter.Wait(ctx, "eth_sendBundle"), flashbotsrpc.ErrRateLimited)
+
+	// Tokens are refilled up to the burst
+	now = now.Add(time.Minute)
+	require.NoError(t, limiter.Wait(ctx, "eth_sendBundle"))
+	require.NoError(t, limiter.Wait(ctx, "eth_call"))
+	require.ErrorIs(t, limiter.Wait(ctx, "eth_call"), flashbotsrpc.ErrRateLimited)
+
+	// A batch takes a token per request of a limited method
+	now = now.Add(time.Minute)
+	require.ErrorIs(t, limiter.Wait(ctx, "eth_sendBundle", "eth_sendBundle"), flashbotsrpc.ErrRateLimited)
+	require.NoError(t, limiter.Wait(ctx, "eth_sendBundle", "eth_call"))
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+	var delays []time.Duration
+	clock := func() time.Time { return now }
+	sleep := func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		now = now.Add(d)
+		return nil
+	}
+	limiter := flashbotsrpc.NewRateLimiter(flashbotsrpc.RateLimit{Rate: 50, Burst: 1}, flashbotsrpc.WithRateLimiterClock(clock, sleep))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, limiter.Wait(context.Background(), "eth_call"))
+	}
+	require.Equal(t, []time.Duration{20 * time.Millisecond, 20 * time.Millisecond}, delays)
+
+	// Waiting stops when the context is done, and the tokens are given back
+	limiter = flashbotsrpc.NewRateLimiter(flashbotsrpc.RateLimit{Rate: 0.001, Burst: 1})
+	require.NoError(t, limiter.Wait(context.Background(), "eth_call"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, limiter.Wait(ctx, "eth_call"), context.DeadlineExceeded)
+	require.InDelta(t, 0, limiter.EndpointTokens(), 0.01)
+
+	// Without a rate, requests aren't limited
+	limiter = flashbotsrpc.NewRateLimiter(flashbotsrpc.RateLimit{})
+	limiter.FailFast = true
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.Wait(context.Background(), "eth_call"))
+	}
+}
+
+func TestRateLimiterClients(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_blockNumber", "0x64")
+
+	limiter := flashbotsrpc.NewRateLimiter(flashbotsrpc.RateLimit{Rate: 0.001, Burst: 1})
+	limiter.FailFast = true
+	rpc := flashbotsrpc.New(node.URL, flashbotsrpc.WithRateLimiter(limiter))
+	_, err := rpc.EthBlockNumber()
+	require.NoError(t, err)
+	_, err = rpc.EthBlockNumber()
+	require.ErrorIs(t, err, flashbotsrpc.ErrRateLimited)
+	require.Len(t, node.Requests(), 1)
+
+	// Each builder has its own limiter
+	limited := flashbotstest.NewNode()
+	defer limited.Close()
+	limited.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	unlimited := flashbotstest.NewNode()
+	defer unlimited.Close()
+	unlimited.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+
+	limiter = flashbotsrpc.NewRateLimiter(flashbotsrpc.RateLimit{Rate: 0.001, Burst: 1})
+	limiter.FailFast = true
+	broadcaster := flashbotsrpc.NewBuilderBroadcastRPC([]string{unlimited.URL}, flashbotsrpc.WithBuilderEndpoints(flashbotsrpc.BuilderEndpoint{URL: limited.URL, RateLimiter: limiter}))
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bundle := flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"}
+	results := broadcaster.BroadcastBundle(key, bundle)
+	require.NoError(t, results[0].Err)
+	require.NoError(t, results[1].Err)
+
+	results = broadcaster.BroadcastBundle(key, bundle)
+	require.NoError(t, results[0].Err)
+	require.ErrorIs(t, results[1].Err, flashbotsrpc.ErrRateLimited)
+
+	// Builders without their own limiter get one with the default limit
+	failFast := func(limiter *flashbotsrpc.RateLimiter) { limiter.FailFast = true }
+	broadcaster = flashbotsrpc.NewBuilderBroadcastRPC([]string{unlimited.URL, limited.URL}, flashbotsrpc.WithBuilderRateLimit(flashbotsrpc.RateLimit{Rate: 0.001, Burst: 1}, failFast))
+	require.NotSame(t, broadcaster.Endpoints()[0].RateLimiter, broadcaster.Endpoints()[1].RateLimiter)
+	results = broadcaster.BroadcastBundle(key, bundle)
+	require.NoError(t, results[0].Err)
+	require.NoError(t, results[1].Err)
+	results = broadcaster.BroadcastBundle(key, bundle)
+	require.ErrorIs(t, results[0].Err, flashbotsrpc.ErrRateLimited)
+	require.ErrorIs(t, results[1].Err, flashbotsrpc.ErrRateLimited)
+
+	// The broadcaster as a whole
+	limiter = flashbotsrpc.NewRateLimiter(flashbotsrpc.RateLimit{Rate: 0.001, Burst: 1}, failFast)
+	broadcaster = flashbotsrpc.NewBuilderBroadcastRPC([]string{unlimited.URL, limited.URL}, flashbotsrpc.WithBroadcastRateLimiter(limiter))
+	results = broadcaster.BroadcastBundle(key, bundle)
+	require.NoError(t, results[0].Err)
+	require.NoError(t, results[1].Err)
+	results = broadcaster.BroadcastBundle(key, bundle)
+	require.ErrorIs(t, results[0].Err, flashbotsrpc.ErrRateLimited)
+	require.ErrorIs(t, results[1].Err, flashbotsrpc.ErrRateLimited)
+}
@@ -0,0 +1,124 @@
+package flashbotsrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxResponseSize is the default limit of response bodies read from a server
+var DefaultMaxResponseSize int64 = 64 << 20 // 64 MB
+
+// ErrResponseTooLarge is returned if a response body exceeds the limit of the client
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// maxBodySnippet is how much of a response body which isn't JSON-RPC is kept in errors
+const maxBodySnippet = 256
+
+// HTTPError is returned by Call, BatchCall and the other methods without Flashbots signature, if the response isn't a
+// JSON-RPC response, e.g. an HTML error page of a proxy, or has a non-2xx HTTP status code without a JSON-RPC error.
+// For signed requests to relays and builders, these responses are returned as RelayError instead.
+type HTTPError struct {
+	Method     string // Method of the request, comma separated for batches
+	Endpoint   string // URL of the server
+	StatusCode int    // HTTP status code of the response
+	Reason     string // Why the response is invalid
+	Body       string // Beginning of the response body, truncated after 256 bytes
+}
+
+func (err *HTTPError) Error() string {
+	return fmt.Sprintf("%s (HTTP status code: %d): %s", err.Reason, err.StatusCode, err.Body)
+}
+
+// httpResponse is a response to a JSON-RPC request, which results and errors are parsed from
+type httpResponse struct {
+	method     string
+	endpoint   string
+	statusCode int
+	body       []byte
+}
+
+// isSuccess returns whether the response has a 2xx HTTP status code
+func (res httpResponse) isSuccess() bool {
+	return res.statusCode >= 200 && res.statusCode < 300
+}
+
+// result returns the result of the response to a request with ID 1. With relay set, relay errors ({"error": "..."})
+// are recognized and all errors are returned as RelayError. Otherwise JSON-RPC errors are returned as RpcError and
+// invalid responses as HTTPError.
+func (res httpResponse) result(relay bool) (json.RawMessage, error) {
+	if relay {
+		if err := res.errorResponse(); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := new(rpcResponse)
+	if err := json.Unmarshal(res.body, resp); err != nil {
+		return nil, res.invalid(relay, "invalid JSON-RPC response")
+	}
+
+	// JSON-RPC errors are returned even with a non-2xx status code, as they describe the failure best
+	if resp.Error != nil {
+		if relay {
+			return nil, res.relayError(resp.Error.Code, resp.Error.Message, resp.Error.Data)
+		}
+		return nil, *resp.Error
+	}
+
+	if !res.isSuccess() {
+		return nil, res.invalid(relay, "unexpected HTTP status code")
+	}
+	if resp.ID != 1 || resp.JSONRPC != "2.0" {
+		return nil, res.invalid(relay, "invalid JSON-RPC response ID or version")
+	}
+	return resp.Result, nil
+}
+
+// invalid returns the error for a response which isn't a valid JSON-RPC response
+func (res httpResponse) invalid(relay bool, reason string) error {
+	httpErr := &HTTPError{
+		Method:     res.method,
+		Endpoint:   res.endpoint,
+		StatusCode: res.statusCode,
+		Reason:     reason,
+		Body:       bodySnippet(res.body),
+	}
+	if relay {
+		return res.relayError(0, httpErr.Error(), nil)
+	}
+	return httpErr
+}
+
+// bodySnippet returns the beginning of a response body for errors
+func bodySnippet(body []byte) string {
+	if len(body) <= maxBodySnippet {
+		return string(body)
+	}
+
+	// Don't cut a multi-byte character in half
+	end := maxBodySnippet
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+	return string(body[:end]) + "..."
+}
+
+// readBody reads a response body up to maxSize bytes, or DefaultMaxResponseSize if maxSize is 0
+func readBody(body io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxResponseSize
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, maxSize)
+	}
+	return data, nil
+}
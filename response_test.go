@@ -0,0 +1,108 @@
+package flashbotsrpc_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		response string
+		reason   string
+	}{
+		{http.StatusBadGateway, `<html><body>502 Bad Gateway</body></html>`, "invalid JSON-RPC response"},
+		{http.StatusServiceUnavailable, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, "unexpected HTTP status code"},
+		{http.StatusOK, `{"jsonrpc":"2.0","id":2,"result":"0x1"}`, "invalid JSON-RPC response ID or version"},
+		{http.StatusOK, `{"id":1,"result":"0x1"}`, "invalid JSON-RPC response ID or version"},
+	}
+	for _, test := range tests {
+		node := flashbotstest.NewNode()
+		node.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: test.status, Body: test.response})
+		_, err := flashbotsrpc.NewFlashbotsRPC(node.URL).Call("eth_blockNumber")
+		node.Close()
+
+		var httpErr *flashbotsrpc.HTTPError
+		require.True(t, errors.As(err, &httpErr), test.response)
+		require.Equal(t, test.reason, httpErr.Reason)
+		require.Equal(t, test.status, httpErr.StatusCode)
+		require.Equal(t, "eth_blockNumber", httpErr.Method)
+		require.Equal(t, test.response, httpErr.Body)
+		require.NotContains(t, err.Error(), "invalid character")
+	}
+
+	// JSON-RPC errors are returned regardless of the status code
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetFault("eth_getBlockByNumber", flashbotstest.Fault{StatusCode: http.StatusInternalServerError, Code: flashbotstest.CodeServerError, Message: "header not found"})
+	_, err := flashbotsrpc.NewFlashbotsRPC(node.URL).Call("eth_getBlockByNumber")
+	require.Equal(t, flashbotsrpc.RpcError{Code: -32000, Message: "header not found"}, err)
+}
+
+func TestCallWithFlashbotsSignatureHTTPStatus(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadGateway, Body: `<html><body>502 Bad Gateway</body></html>`})
+	_, err = flashbotsrpc.NewFlashbotsRPC(node.URL).CallWithFlashbotsSignature("eth_sendBundle", key)
+	require.ErrorIs(t, err, flashbotsrpc.ErrRelayErrorResponse)
+	var relayErr *flashbotsrpc.RelayError
+	require.True(t, errors.As(err, &relayErr))
+	require.Equal(t, http.StatusBadGateway, relayErr.StatusCode)
+	require.Contains(t, relayErr.Message, "502 Bad Gateway")
+
+	// Builders answer the same way
+	results := flashbotsrpc.NewBuilderBroadcastRPC([]string{node.URL}).BroadcastBundle(key, flashbotsrpc.FlashbotsSendBundleRequest{})
+	require.ErrorIs(t, results[0].Err, flashbotsrpc.ErrRelayErrorResponse)
+	require.Contains(t, results[0].Err.Error(), "502 Bad Gateway")
+
+	node.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusTooManyRequests, Body: `<html><body>Too Many Requests</body></html>`})
+	_, err = flashbotsrpc.NewFlashbotsRPC(node.URL).CallWithFlashbotsSignature("eth_sendBundle", key)
+	require.ErrorIs(t, err, flashbotsrpc.ErrRelayRateLimited)
+	require.NotErrorIs(t, err, flashbotsrpc.ErrRateLimited)
+}
+
+func TestBatchCallHTTPStatus(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadGateway, Body: `<html><body>502 Bad Gateway</body></html>`})
+
+	batch := []flashbotsrpc.BatchElem{{Method: "eth_blockNumber"}, {Method: "eth_chainId"}}
+	err := flashbotsrpc.NewFlashbotsRPC(node.URL).BatchCall(batch)
+	var httpErr *flashbotsrpc.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	require.Equal(t, "eth_blockNumber,eth_chainId", httpErr.Method)
+
+	node.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{Body: `[{"id":1,"result":"0x1"}]`})
+	err = flashbotsrpc.NewFlashbotsRPC(node.URL).BatchCall(batch)
+	require.True(t, errors.As(err, &httpErr))
+}
+
+func TestResponseSize(t *testing.T) {
+	page := "<html>" + strings.Repeat("é", 200) + "</html>"
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadGateway, Body: page})
+
+	rpc := flashbotsrpc.NewFlashbotsRPC(node.URL)
+	_, err := rpc.Call("eth_blockNumber")
+	var httpErr *flashbotsrpc.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.True(t, strings.HasPrefix(page, strings.TrimSuffix(httpErr.Body, "...")))
+	require.True(t, strings.HasSuffix(httpErr.Body, "..."))
+	require.LessOrEqual(t, len(httpErr.Body), flashbotsrpc.MaxBodySnippet+3)
+
+	rpc.MaxResponseSize = 100
+	_, err = rpc.Call("eth_blockNumber")
+	require.ErrorIs(t, err, flashbotsrpc.ErrResponseTooLarge)
+}
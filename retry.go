@@ -0,0 +1,195 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultRetryPolicy retries failed requests twice, after 100ms and 200ms (up to 20% less with jitter)
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Jitter:         0.2,
+}
+
+// RetryPolicy configures how FlashbotsRPC retries requests which failed with a transport error or a retryable HTTP status
+// code (429, 502, 503, 504). The zero value makes a single attempt.
+//
+// Requests of idempotent methods (see IsIdempotentMethod) are retried after every retryable failure. All other requests
+// are only retried when the server can't have processed them: if the connection couldn't be established, or the server
+// answered with 429 Too Many Requests. A bundle or transaction is therefore never submitted twice because of a retry.
+// Batches are idempotent if all their methods are.
+//
+// If retries are exhausted, the last response or error is returned as if there was no retry policy.
+type RetryPolicy struct {
+	MaxAttempts    int           // Total number of attempts, values below 2 disable retries
+	InitialBackoff time.Duration // Backoff before the first retry, doubled for each further retry
+	MaxBackoff     time.Duration // (Optional) Upper bound of the backoff and of Retry-After
+	Jitter         float64       // (Optional) Randomly shorten each backoff by up to this fraction, between 0 and 1
+
+	// (Optional) Retryable decides whether a failed attempt is retried, defaults to IsRetryable. err is the transport
+	// error, statusCode the HTTP status code if there was a response.
+	Retryable func(statusCode int, err error) bool
+
+	// (Optional) IdempotentMethods are additional methods which are safe to retry, e.g. eth_sendBundle, which the relay
+	// deduplicates by bundle hash.
+	IdempotentMethods []string
+}
+
+// IsIdempotentMethod returns whether a request of the method can be sent again without changing the outcome. These are
+// the methods which only read state: web3_*, net_*, eth_call, eth_estimateGas, eth_callBundle, the eth_get methods for
+// accounts, blocks, transactions, receipts and logs, flashbots_getUserStats(V2), flashbots_getBundleStats(V2) and a few
+// other read-only eth_ methods. eth_getFilterChanges isn't idempotent, the node only returns each change once.
+func IsIdempotentMethod(method string) bool {
+	switch method {
+	case "eth_call", "eth_estimateGas", "eth_callBundle", "eth_blockNumber", "eth_chainId", "eth_gasPrice",
+		"eth_maxPriorityFeePerGas", "eth_feeHistory", "eth_syncing", "eth_protocolVersion", "eth_coinbase", "eth_mining",
+		"eth_hashrate", "eth_accounts",
+		"eth_getBalance", "eth_getCode", "eth_getStorageAt", "eth_getTransactionCount", "eth_getProof",
+		"eth_getBlockByNumber", "eth_getBlockByHash", "eth_getBlockReceipts",
+		"eth_getBlockTransactionCountByNumber", "eth_getBlockTransactionCountByHash",
+		"eth_getUncleByBlockNumberAndIndex", "eth_getUncleByBlockHashAndIndex",
+		"eth_getUncleCountByBlockNumber", "eth_getUncleCountByBlockHash",
+		"eth_getTransactionByHash", "eth_getTransactionByBlockNumberAndIndex", "eth_getTransactionByBlockHashAndIndex",
+		"eth_getTransactionReceipt", "eth_getLogs", "eth_getFilterLogs",
+		"flashbots_getUserStats", "flashbots_getUserStatsV2", "flashbots_getBundleStats", "flashbots_getBundleStatsV2":
+		return true
+	}
+	return strings.HasPrefix(method, "web3_") || strings.HasPrefix(method, "net_")
+}
+
+// IsRetryable is the default RetryPolicy.Retryable. It retries network errors (failed dials, reads and writes, connections
+// closed by the server and timeouts of the attempt, like the http.Client Timeout, but not context cancellation) and the
+// HTTP status codes 429, 502, 503 and 504. Errors which would happen again, like ErrResponseTooLarge or invalid
+// responses, aren't retried. Requests are never retried once the caller's context is done.
+func IsRetryable(statusCode int, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && isNetworkError(err)
+	}
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isNetworkError returns whether err is a failure of the connection to the server
+func isNetworkError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isIdempotent returns whether all methods are idempotent with this policy
+func (policy RetryPolicy) isIdempotent(methods ...string) bool {
+	for _, method := range methods {
+		if !IsIdempotentMethod(method) && !policy.isIdempotentMethod(method) {
+			return false
+		}
+	}
+	return true
+}
+
+func (policy RetryPolicy) isIdempotentMethod(method string) bool {
+	for _, m := range policy.IdempotentMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldRetry returns whether a failed attempt is retried, see RetryPolicy
+func (policy RetryPolicy) shouldRetry(attempt, statusCode int, err error, idempotent bool) bool {
+	if attempt >= policy.MaxAttempts {
+		return false
+	}
+
+	retryable := IsRetryable
+	if policy.Retryable != nil {
+		retryable = policy.Retryable
+	}
+	if !retryable(statusCode, err) {
+		return false
+	}
+	return idempotent || notProcessed(statusCode, err)
+}
+
+// notProcessed returns whether a failed request certainly wasn't processed by the server
+func notProcessed(statusCode int, err error) bool {
+	if err == nil {
+		return statusCode == http.StatusTooManyRequests
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// backoff returns how long to wait before the retry after the given attempt. retryAfter is used instead of the
+// exponential backoff if it is set.
+func (policy RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	backoff := retryAfter
+	if backoff <= 0 {
+		backoff = policy.InitialBackoff
+		for i := 1; i < attempt && (policy.MaxBackoff <= 0 || backoff < policy.MaxBackoff); i++ {
+			backoff *= 2
+		}
+		if policy.Jitter > 0 {
+			backoff -= time.Duration(rand.Float64() * policy.Jitter * float64(backoff))
+		}
+	}
+
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+	return backoff
+}
+
+// parseRetryAfter returns the duration of a Retry-After header, which is either in seconds or an HTTP date. It returns 0
+// if the header is empty or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// sleepContext waits for the duration, or returns the error of the context if it is done before
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,216 @@
+package flashbotsrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = flashbotsrpc.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+func TestRetryIdempotent(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_blockNumber", "0x64")
+	node.InjectFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadGateway}, flashbotstest.Fault{StatusCode: http.StatusServiceUnavailable})
+
+	rpc := flashbotsrpc.New(node.URL, flashbotsrpc.WithRetryPolicy(testRetryPolicy))
+	blockNumber, err := rpc.EthBlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, 100, blockNumber)
+	require.Len(t, node.Requests(), 3)
+
+	// Retries are exhausted
+	badGateway := flashbotstest.Fault{StatusCode: http.StatusBadGateway}
+	node.InjectFault(flashbotstest.AnyMethod, badGateway, badGateway, badGateway)
+	_, err = rpc.EthBlockNumber()
+	require.Error(t, err)
+	require.Len(t, node.Requests(), 6)
+
+	// No retries by default
+	node.InjectFault(flashbotstest.AnyMethod, badGateway)
+	_, err = flashbotsrpc.New(node.URL).EthBlockNumber()
+	require.Error(t, err)
+	require.Len(t, node.Requests(), 7)
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bundle := flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"}
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+	rpc := flashbotsrpc.New(node.URL, flashbotsrpc.WithRetryPolicy(testRetryPolicy))
+
+	// The relay may have processed the bundle before the gateway failed
+	node.InjectFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadGateway})
+	_, err = rpc.FlashbotsSendBundle(key, bundle)
+	require.Error(t, err)
+	require.Len(t, node.Requests(), 1)
+
+	// Rate limited requests weren't processed
+	node.InjectFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusTooManyRequests})
+	res, err := rpc.FlashbotsSendBundle(key, bundle)
+	require.NoError(t, err)
+	require.Equal(t, "0x1234", res.BundleHash)
+	require.Len(t, node.Requests(), 3)
+
+	// Unless the method is configured as idempotent
+	node.InjectFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadGateway})
+	policy := testRetryPolicy
+	policy.IdempotentMethods = []string{"eth_sendBundle"}
+	_, err = flashbotsrpc.New(node.URL, flashbotsrpc.WithRetryPolicy(policy)).FlashbotsSendBundle(key, bundle)
+	require.NoError(t, err)
+	require.Len(t, node.Requests(), 5)
+
+	// Connections which couldn't be established are retried
+	closed := flashbotstest.NewNode()
+	closed.Close()
+	var classified int32
+	policy = testRetryPolicy
+	policy.Retryable = func(statusCode int, err error) bool {
+		atomic.AddInt32(&classified, 1)
+		return flashbotsrpc.IsRetryable(statusCode, err)
+	}
+	_, err = flashbotsrpc.New(closed.URL, flashbotsrpc.WithRetryPolicy(policy)).FlashbotsSendBundle(key, bundle)
+	require.Error(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&classified))
+}
+
+func TestRetryDeterministicFailures(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_blockNumber", strings.Repeat("0", 200))
+
+	// A response which is too large would be too large again
+	rpc := flashbotsrpc.New(node.URL, flashbotsrpc.WithRetryPolicy(testRetryPolicy))
+	rpc.MaxResponseSize = 100
+	_, err := rpc.EthBlockNumber()
+	require.ErrorIs(t, err, flashbotsrpc.ErrResponseTooLarge)
+	require.Len(t, node.Requests(), 1)
+
+	// So would an invalid response
+	node.InjectFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusInternalServerError})
+	_, err = flashbotsrpc.New(node.URL, flashbotsrpc.WithRetryPolicy(testRetryPolicy)).EthBlockNumber()
+	require.Error(t, err)
+	require.Len(t, node.Requests(), 2)
+}
+
+// timeoutError is like the error of an http.Client which exceeded its Timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "Client.Timeout exceeded while awaiting headers" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+func (timeoutError) Is(err error) bool {
+	return err == context.DeadlineExceeded
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		err        error
+		retryable  bool
+	}{
+		{0, &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{0, &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, true},
+		{0, io.ErrUnexpectedEOF, true},
+		{http.StatusBadGateway, nil, true},
+		{http.StatusTooManyRequests, nil, true},
+		{0, context.Canceled, false},
+		{0, &url.Error{Op: "Post", URL: "http://localhost", Err: timeoutError{}}, true},
+		{0, flashbotsrpc.ErrResponseTooLarge, false},
+		{0, &json.SyntaxError{}, false},
+		{0, &flashbotsrpc.RelayError{Message: "bundle rejected"}, false},
+		{0, flashbotsrpc.RpcError{Code: -32000, Message: "header not found"}, false},
+		{http.StatusInternalServerError, nil, false},
+		{http.StatusOK, nil, false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.retryable, flashbotsrpc.IsRetryable(test.statusCode, test.err), "%d %v", test.statusCode, test.err)
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_blockNumber", "0x64")
+	node.InjectFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusBadGateway})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	rpc := flashbotsrpc.New(node.URL, flashbotsrpc.WithRetryPolicy(flashbotsrpc.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour}))
+	_, err := rpc.EthBlockNumberContext(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Len(t, node.Requests(), 1)
+}
+
+func TestRetryTimeout(t *testing.T) {
+	// The first attempt exceeds the client timeout, the second one is answered immediately
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_blockNumber", "0x64")
+	node.InjectFault(flashbotstest.AnyMethod, flashbotstest.Fault{StatusCode: http.StatusGatewayTimeout, Delay: time.Second})
+
+	rpc := flashbotsrpc.New(node.URL, flashbotsrpc.WithRetryPolicy(testRetryPolicy), flashbotsrpc.WithHttpClient(&http.Client{Timeout: 50 * time.Millisecond}))
+	blockNumber, err := rpc.EthBlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, 100, blockNumber)
+	require.Len(t, node.Requests(), 2)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := flashbotsrpc.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	require.Equal(t, 100*time.Millisecond, policy.Backoff(1, 0))
+	require.Equal(t, 200*time.Millisecond, policy.Backoff(2, 0))
+	require.Equal(t, 800*time.Millisecond, policy.Backoff(4, 0))
+	require.Equal(t, time.Second, policy.Backoff(5, 0))
+	require.Equal(t, time.Second, policy.Backoff(100, 0))
+
+	// Retry-After is used instead, up to MaxBackoff
+	require.Equal(t, 500*time.Millisecond, policy.Backoff(1, 500*time.Millisecond))
+	require.Equal(t, time.Second, policy.Backoff(1, time.Minute))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		backoff := policy.Backoff(2, 0)
+		require.True(t, backoff > 100*time.Millisecond && backoff <= 200*time.Millisecond, backoff)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 4, 1, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, 2*time.Second, flashbotsrpc.ParseRetryAfter("2", now))
+	require.Equal(t, 30*time.Second, flashbotsrpc.ParseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	require.Equal(t, time.Duration(0), flashbotsrpc.ParseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	require.Equal(t, time.Duration(0), flashbotsrpc.ParseRetryAfter("", now))
+	require.Equal(t, time.Duration(0), flashbotsrpc.ParseRetryAfter("soon", now))
+	require.Equal(t, time.Duration(0), flashbotsrpc.ParseRetryAfter("-1", now))
+}
+
+func TestIsIdempotentMethod(t *testing.T) {
+	for _, method := range []string{"eth_blockNumber", "eth_getBalance", "eth_getLogs", "eth_getTransactionReceipt", "eth_call", "eth_callBundle", "net_version", "flashbots_getBundleStatsV2"} {
+		require.True(t, flashbotsrpc.IsIdempotentMethod(method), method)
+	}
+	for _, method := range []string{"eth_sendBundle", "eth_sendRawTransaction", "eth_sendPrivateTransaction", "eth_cancelBundle", "mev_sendBundle", "eth_getFilterChanges", "eth_getWork"} {
+		require.False(t, flashbotsrpc.IsIdempotentMethod(method), method)
+	}
+
+	policy := flashbotsrpc.RetryPolicy{IdempotentMethods: []string{"eth_sendBundle"}}
+	require.True(t, policy.IsIdempotent("eth_sendBundle", "eth_blockNumber"))
+	require.False(t, policy.IsIdempotent("eth_sendBundle", "eth_sendRawTransaction"))
+}
@@ -0,0 +1,208 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// DefaultPendingTimeout is how long PrivateTxRouter counts a private transaction as pending if it isn't mined
+var DefaultPendingTimeout = 5 * time.Minute
+
+// PrivateTxRouter is an http.Handler which looks like a normal Ethereum node to wallets, but keeps transactions out of the
+// public mempool: eth_sendRawTransaction is sent to the relay with eth_sendPrivateTransaction, or broadcast to builders,
+// and eth_getTransactionCount with the "pending" block includes the private transactions which aren't mined yet. All other
+// methods are forwarded to the node. Batch requests are supported.
+//
+// Like ProxyHandler, it only accepts application/json requests, and rejects requests from browsers unless their origin
+// is allowed with WithPrivateTxRouterAllowedOrigins.
+type PrivateTxRouter struct {
+	node           *FlashbotsRPC
+	relay          *FlashbotsRPC
+	broadcaster    *BuilderBroadcastRPC
+	signer         Signer
+	log            logger
+	allowedOrigins []string
+
+	lock    sync.Mutex
+	pending map[common.Address]map[uint64]pendingPrivateTx
+
+	Debug          bool
+	MaxBodySize    int64                          // Larger requests are rejected with HTTP 413
+	PendingTimeout time.Duration                  // Private transactions are no longer counted as pending after this
+	Preferences    *FlashbotsPrivateTxPreferences // Sent with each private transaction
+}
+
+type pendingPrivateTx struct {
+	hash   common.Hash
+	sentAt time.Time
+}
+
+// NewPrivateTxRouter creates a router which forwards requests to node, and signs private transactions with signer and
+// sends them to relay. relay may be nil if the transactions are broadcast to builders (see WithPrivateTxRouterBroadcaster).
+func NewPrivateTxRouter(node, relay *FlashbotsRPC, signer Signer, options ...func(router *PrivateTxRouter)) (*PrivateTxRouter, error) {
+	router := &PrivateTxRouter{
+		node:           node,
+		relay:          relay,
+		signer:         signer,
+		log:            log.New(os.Stderr, "", log.LstdFlags),
+		pending:        make(map[common.Address]map[uint64]pendingPrivateTx),
+		MaxBodySize:    DefaultMaxSignedBodySize,
+		PendingTimeout: DefaultPendingTimeout,
+	}
+	for _, option := range options {
+		option(router)
+	}
+
+	switch {
+	case router.node == nil:
+		return nil, errors.New("private tx router: node is required")
+	case router.relay == nil && router.broadcaster == nil:
+		return nil, errors.New("private tx router: relay or broadcaster is required")
+	case router.signer == nil:
+		return nil, errors.New("private tx router: signer is required")
+	}
+	return router, nil
+}
+
+func (router *PrivateTxRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveJSONRPC(w, r, router.MaxBodySize, router.allowedOrigins, router.handle)
+}
+
+// handle routes a single request and returns the response for the wallet
+func (router *PrivateTxRouter) handle(ctx context.Context, request proxyRequest) proxyResponse {
+	if request.Method == "" {
+		return proxyErrorResponse(request.ID, proxyCodeInvalidRequest, "missing method")
+	}
+
+	var result json.RawMessage
+	var err error
+	switch request.Method {
+	case "eth_sendRawTransaction":
+		var rawTx string
+		if len(request.Params) != 1 || json.Unmarshal(request.Params[0], &rawTx) != nil {
+			return proxyErrorResponse(request.ID, proxyCodeInvalidParams, "expected the signed transaction as only param")
+		}
+		result, err = router.sendRawTransaction(ctx, rawTx)
+	case "eth_getTransactionCount":
+		result, err = router.getTransactionCount(ctx, request)
+	default:
+		result, err = router.node.CallContext(ctx, request.Method, request.params()...)
+	}
+
+	if router.Debug {
+		router.log.Println(fmt.Sprintf("router: %s: result: %s, error: %v", request.Method, result, err))
+	}
+	return proxyResultResponse(request.ID, result, err)
+}
+
+// sendRawTransaction sends the transaction privately and records it as pending
+func (router *PrivateTxRouter) sendRawTransaction(ctx context.Context, rawTx string) (json.RawMessage, error) {
+	txs, err := decodeBundleTxs([]string{rawTx})
+	if err != nil {
+		return nil, RpcError{Code: proxyCodeInvalidParams, Message: err.Error()}
+	}
+	tx := txs[0]
+
+	param := FlashbotsSendPrivateTransactionRequest{Tx: rawTx, Preferences: router.Preferences}
+	if router.broadcaster != nil {
+		responses := router.broadcaster.BroadcastPrivateTransactionContext(ctx, router.signer, param)
+		results := make([]BuilderBroadcastResult, len(responses))
+		for i, response := range responses {
+			results[i] = response.BuilderBroadcastResult
+		}
+		if _, err := firstSuccess(results); err != nil {
+			return nil, err
+		}
+	} else if _, err := router.relay.FlashbotsSendPrivateTransactionContext(ctx, router.signer, param); err != nil {
+		return nil, err
+	}
+
+	router.lock.Lock()
+	if router.pending[tx.from] == nil {
+		router.pending[tx.from] = make(map[uint64]pendingPrivateTx)
+	}
+	router.pending[tx.from][tx.nonce] = pendingPrivateTx{hash: tx.hash, sentAt: time.Now()}
+	router.lock.Unlock()
+
+	return json.Marshal(tx.hash)
+}
+
+// getTransactionCount returns the transaction count of the node, which for the "pending" block is increased by the
+// private transactions with consecutive nonces
+func (router *PrivateTxRouter) getTransactionCount(ctx context.Context, request proxyRequest) (json.RawMessage, error) {
+	result, err := router.node.CallContext(ctx, request.Method, request.params()...)
+	if err != nil || len(request.Params) != 2 {
+		return result, err
+	}
+
+	var address common.Address
+	var block string
+	if json.Unmarshal(request.Params[0], &address) != nil || json.Unmarshal(request.Params[1], &block) != nil || !strings.EqualFold(block, "pending") {
+		return result, nil
+	}
+
+	var count hexutil.Uint64
+	if err := json.Unmarshal(result, &count); err != nil {
+		return nil, err
+	}
+	return json.Marshal(hexutil.Uint64(router.pendingNonce(address, uint64(count))))
+}
+
+// pendingNonce prunes the pending transactions of address which were mined (nonce below count) or timed out, and
+// returns the nonce after the private transactions which follow count
+func (router *PrivateTxRouter) pendingNonce(address common.Address, count uint64) uint64 {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+
+	pending := router.pending[address]
+	for nonce, tx := range pending {
+		if nonce < count || time.Since(tx.sentAt) > router.PendingTimeout {
+			delete(pending, nonce)
+		}
+	}
+	if len(pending) == 0 {
+		delete(router.pending, address)
+		return count
+	}
+
+	for {
+		if _, ok := pending[count]; !ok {
+			return count
+		}
+		count++
+	}
+}
+
+// WithPrivateTxRouterBroadcaster broadcasts private transactions to builders instead of sending them to the relay. A
+// transaction is accepted if any builder accepted it.
+func WithPrivateTxRouterBroadcaster(broadcaster *BuilderBroadcastRPC) func(router *PrivateTxRouter) {
+	return func(router *PrivateTxRouter) {
+		router.broadcaster = broadcaster
+	}
+}
+
+// WithPrivateTxRouterAllowedOrigins accepts requests from browsers with one of the given origins (e.g. the origin of a
+// wallet extension, or "*" for all), which are rejected by default
+func WithPrivateTxRouterAllowedOrigins(origins ...string) func(router *PrivateTxRouter) {
+	return func(router *PrivateTxRouter) {
+		router.allowedOrigins = append(router.allowedOrigins, origins...)
+	}
+}
+
+// WithPrivateTxRouterLogger set custom logger for the router
+func WithPrivateTxRouterLogger(l logger) func(router *PrivateTxRouter) {
+	return func(router *PrivateTxRouter) {
+		router.log = l
+	}
+}
@@ -0,0 +1,235 @@
+package flashbotsrpc_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newRawTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64) (string, string) {
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21000,
+	}), types.LatestSignerForChainID(big.NewInt(1)), key)
+	require.NoError(t, err)
+	data, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return hexutil.Encode(data), tx.Hash().Hex()
+}
+
+// callRouter sends a request to the router and returns the response
+func callRouter(t *testing.T, url, method string, params ...interface{}) gjson.Result {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+	res, err := http.Post(url, "application/json", strings.NewReader(string(body)))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return gjson.ParseBytes(data)
+}
+
+func TestPrivateTxRouter(t *testing.T) {
+	relay := flashbotstest.NewRelay()
+	defer relay.Close()
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult(flashbotstest.AnyMethod, "0x1")
+	node.SetResult("eth_getTransactionCount", "0x0")
+
+	signerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	router, err := flashbotsrpc.NewPrivateTxRouter(flashbotsrpc.New(node.URL), flashbotsrpc.New(relay.URL), flashbotsrpc.NewPrivateKeySigner(signerKey))
+	require.NoError(t, err)
+	router.Preferences = &flashbotsrpc.FlashbotsPrivateTxPreferences{Fast: true}
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	node.SetResult("eth_getTransactionCount", hexutil.EncodeUint64(3))
+
+	// Transactions are sent to the relay as private transactions
+	rawTx, txHash := newRawTx(t, key, 3)
+	res := callRouter(t, server.URL, "eth_sendRawTransaction", rawTx)
+	require.Equal(t, txHash, res.Get("result").String())
+	rawTx2, _ := newRawTx(t, key, 4)
+	callRouter(t, server.URL, "eth_sendRawTransaction", rawTx2)
+	require.Equal(t, []flashbotsrpc.FlashbotsSendPrivateTransactionRequest{
+		{Tx: rawTx, Preferences: router.Preferences},
+		{Tx: rawTx2, Preferences: router.Preferences},
+	}, relay.PrivateTransactions())
+
+	// The pending nonce includes the private transactions, the latest doesn't
+	res = callRouter(t, server.URL, "eth_getTransactionCount", address, "pending")
+	require.Equal(t, "0x5", res.Get("result").String())
+	res = callRouter(t, server.URL, "eth_getTransactionCount", address, "latest")
+	require.Equal(t, "0x3", res.Get("result").String())
+
+	// Mined transactions are no longer pending
+	node.SetResult("eth_getTransactionCount", hexutil.EncodeUint64(4))
+	res = callRouter(t, server.URL, "eth_getTransactionCount", address, "pending")
+	require.Equal(t, "0x5", res.Get("result").String())
+	node.SetResult("eth_getTransactionCount", hexutil.EncodeUint64(5))
+	res = callRouter(t, server.URL, "eth_getTransactionCount", address, "pending")
+	require.Equal(t, "0x5", res.Get("result").String())
+
+	// All other methods go to the node
+	res = callRouter(t, server.URL, "eth_chainId")
+	require.Equal(t, "0x1", res.Get("result").String())
+
+	res = callRouter(t, server.URL, "eth_sendRawTransaction", "0x1234")
+	require.Equal(t, int64(-32602), res.Get("error.code").Int())
+
+	// Relay errors are passed on, and the transaction isn't pending
+	relay.InjectFault("eth_sendPrivateTransaction", flashbotstest.Fault{Code: flashbotstest.CodeServerError, Message: "tx rejected"})
+	rawTx, _ = newRawTx(t, key, 5)
+	res = callRouter(t, server.URL, "eth_sendRawTransaction", rawTx)
+	require.Contains(t, res.Get("error.message").String(), "tx rejected")
+	res = callRouter(t, server.URL, "eth_getTransactionCount", address, "pending")
+	require.Equal(t, "0x5", res.Get("result").String())
+}
+
+func TestPrivateTxRouterBroadcast(t *testing.T) {
+	failing := flashbotstest.NewRelay()
+	defer failing.Close()
+	unavailable := flashbotstest.Fault{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"}
+	failing.InjectFault("eth_sendPrivateTransaction", unavailable, unavailable)
+	accepting := flashbotstest.NewRelay()
+	defer accepting.Close()
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult(flashbotstest.AnyMethod, "0x1")
+	node.SetResult("eth_getTransactionCount", "0x0")
+
+	signerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	broadcaster := flashbotsrpc.NewBuilderBroadcastRPC([]string{failing.URL, accepting.URL})
+	router, err := flashbotsrpc.NewPrivateTxRouter(flashbotsrpc.New(node.URL), nil, flashbotsrpc.NewPrivateKeySigner(signerKey), flashbotsrpc.WithPrivateTxRouterBroadcaster(broadcaster))
+	require.NoError(t, err)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	rawTx, txHash := newRawTx(t, key, 0)
+	res := callRouter(t, server.URL, "eth_sendRawTransaction", rawTx)
+	require.Equal(t, txHash, res.Get("result").String())
+	require.Len(t, accepting.PrivateTransactions(), 1)
+
+	res = callRouter(t, server.URL, "eth_getTransactionCount", crypto.PubkeyToAddress(key.PublicKey).Hex(), "pending")
+	require.Equal(t, "0x1", res.Get("result").String())
+
+	// The transaction is rejected if no builder accepted it
+	accepting.InjectFault("eth_sendPrivateTransaction", unavailable)
+	rawTx, _ = newRawTx(t, key, 1)
+	res = callRouter(t, server.URL, "eth_sendRawTransaction", rawTx)
+	require.Contains(t, res.Get("error.message").String(), flashbotsrpc.ErrAllBuildersFailed.Error())
+}
+
+func TestPrivateTxRouterPendingTimeout(t *testing.T) {
+	relay := flashbotstest.NewRelay()
+	defer relay.Close()
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult(flashbotstest.AnyMethod, "0x1")
+	node.SetResult("eth_getTransactionCount", "0x0")
+
+	signerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	router, err := flashbotsrpc.NewPrivateTxRouter(flashbotsrpc.New(node.URL), flashbotsrpc.New(relay.URL), flashbotsrpc.NewPrivateKeySigner(signerKey))
+	require.NoError(t, err)
+	router.PendingTimeout = time.Nanosecond
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// Timed out transactions are no longer pending
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	node.SetResult("eth_getTransactionCount", hexutil.EncodeUint64(3))
+	rawTx, _ := newRawTx(t, key, 3)
+	callRouter(t, server.URL, "eth_sendRawTransaction", rawTx)
+	res := callRouter(t, server.URL, "eth_getTransactionCount", crypto.PubkeyToAddress(key.PublicKey).Hex(), "pending")
+	require.Equal(t, "0x3", res.Get("result").String())
+	require.Len(t, relay.PrivateTransactions(), 1)
+}
+
+func TestPrivateTxRouterBrowserRequests(t *testing.T) {
+	relay := flashbotstest.NewRelay()
+	defer relay.Close()
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult(flashbotstest.AnyMethod, "0x1")
+	node.SetResult("eth_getTransactionCount", "0x0")
+
+	signerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	router, err := flashbotsrpc.NewPrivateTxRouter(flashbotsrpc.New(node.URL), flashbotsrpc.New(relay.URL), flashbotsrpc.NewPrivateKeySigner(signerKey),
+		flashbotsrpc.WithPrivateTxRouterAllowedOrigins("chrome-extension://wallet"))
+	require.NoError(t, err)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	rawTx, _ := newRawTx(t, key, 0)
+	body := `{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["` + rawTx + `"]}`
+	post := func(contentType, origin string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		return res
+	}
+
+	// Simple CORS requests from any website are rejected before a transaction is sent
+	require.Equal(t, http.StatusUnsupportedMediaType, post("text/plain", "").StatusCode)
+	require.Equal(t, http.StatusForbidden, post("application/json", "https://evil.example.com").StatusCode)
+	require.Empty(t, relay.PrivateTransactions())
+
+	res := post("application/json", "chrome-extension://wallet")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "chrome-extension://wallet", res.Header.Get("Access-Control-Allow-Origin"))
+	require.Len(t, relay.PrivateTransactions(), 1)
+}
+
+func TestNewPrivateTxRouter(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := flashbotsrpc.NewPrivateKeySigner(key)
+	node := flashbotsrpc.New("http://localhost:8545")
+
+	_, err = flashbotsrpc.NewPrivateTxRouter(node, nil, signer)
+	require.Error(t, err)
+	_, err = flashbotsrpc.NewPrivateTxRouter(nil, flashbotsrpc.New("http://localhost:18545"), signer)
+	require.Error(t, err)
+	_, err = flashbotsrpc.NewPrivateTxRouter(node, flashbotsrpc.New("http://localhost:18545"), nil)
+	require.Error(t, err)
+	_, err = flashbotsrpc.NewPrivateTxRouter(node, nil, signer, flashbotsrpc.WithPrivateTxRouterBroadcaster(flashbotsrpc.NewBuilderBroadcastRPC(nil)))
+	require.NoError(t, err)
+}
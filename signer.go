@@ -0,0 +1,201 @@
+package flashbotsrpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// ErrSignerAddressMismatch means a signature doesn't recover to the address of the signer
+var ErrSignerAddressMismatch = errors.New("signature does not match signer address")
+
+// Signer creates the signatures for the X-Flashbots-Signature header
+type Signer interface {
+	// Address returns the address the signatures recover to
+	Address() common.Address
+	// SignHashContext signs a 32 byte hash and returns the signature in the [R || S || V] format, where V is 0 or 1.
+	// Signers which call other services stop when ctx is done.
+	SignHashContext(ctx context.Context, hash []byte) ([]byte, error)
+}
+
+// PrivateKeySigner signs with a private key held in memory
+type PrivateKeySigner struct {
+	privKey *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner returns a signer for the given private key
+func NewPrivateKeySigner(privKey *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{
+		privKey: privKey,
+		address: crypto.PubkeyToAddress(privKey.PublicKey),
+	}
+}
+
+// Address returns the address of the private key
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignHash signs the hash with the private key
+func (s *PrivateKeySigner) SignHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.privKey)
+}
+
+// SignHashContext is like SignHash, signing with a key in memory doesn't block
+func (s *PrivateKeySigner) SignHashContext(ctx context.Context, hash []byte) ([]byte, error) {
+	return s.SignHash(hash)
+}
+
+// NewKeystoreSigner decrypts a go-ethereum keystore file with the given passphrase and returns a signer for its key
+func NewKeystoreSigner(path, passphrase string) (*PrivateKeySigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrivateKeySigner(key.PrivateKey), nil
+}
+
+// RemoteSignRequest is the request body a RemoteSigner POSTs to the signing service
+type RemoteSignRequest struct {
+	Address common.Address `json:"address"` // Address of the key that should be used
+	Hash    hexutil.Bytes  `json:"hash"`    // 32 byte hash to sign
+}
+
+// RemoteSignResponse is the response body expected from the signing service. On failure, the service
+// should respond with a non-200 status code and the reason in Error.
+type RemoteSignResponse struct {
+	Signature hexutil.Bytes `json:"signature,omitempty"` // 65 byte signature in the [R || S || V] format
+	Error     string        `json:"error,omitempty"`
+}
+
+// RemoteSigner asks a signing service over HTTP to sign hashes, so the key doesn't have to be in process memory.
+//
+// Every SignHash call POSTs a RemoteSignRequest as JSON to the service url, which responds with a RemoteSignResponse.
+// The returned signature is verified to recover to the configured address. V may be 27 or 28, it is returned as 0 or 1.
+type RemoteSigner struct {
+	url     string
+	address common.Address
+	client  httpClient
+	Headers map[string]string // Additional headers to send with the request, e.g. for authentication
+	Timeout time.Duration
+}
+
+// NewRemoteSigner returns a signer for the key with the given address, held by the signing service at url
+func NewRemoteSigner(url string, address common.Address, options ...func(signer *RemoteSigner)) *RemoteSigner {
+	signer := &RemoteSigner{
+		url:     url,
+		address: address,
+		Headers: make(map[string]string),
+		Timeout: 10 * time.Second,
+	}
+	for _, option := range options {
+		option(signer)
+	}
+	if signer.client == nil {
+		signer.client = &http.Client{
+			Timeout: signer.Timeout,
+		}
+	}
+	return signer
+}
+
+// Address returns the address of the remote key
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignHash requests a signature for the hash from the signing service
+func (s *RemoteSigner) SignHash(hash []byte) ([]byte, error) {
+	return s.SignHashContext(context.Background(), hash)
+}
+
+// SignHashContext is like SignHash but takes a context for cancellation and deadlines
+func (s *RemoteSigner) SignHashContext(ctx context.Context, hash []byte) ([]byte, error) {
+	body, err := json.Marshal(RemoteSignRequest{Address: s.address, Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Add(k, v)
+	}
+
+	response, err := s.client.Do(req)
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(RemoteSignResponse)
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, fmt.Errorf("remote signer: invalid response (HTTP status code: %d): %w", response.StatusCode, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("remote signer: %s", resp.Error)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer: HTTP status code %d", response.StatusCode)
+	}
+
+	sig := normalizeSignature(resp.Signature)
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	if crypto.PubkeyToAddress(*pubKey) != s.address {
+		return nil, ErrSignerAddressMismatch
+	}
+
+	return sig, nil
+}
+
+// normalizeSignature returns a [R || S || V] signature with V as 0 or 1, as wallets and signing services often use
+// 27 and 28
+func normalizeSignature(sig []byte) []byte {
+	if len(sig) != crypto.SignatureLength || sig[crypto.RecoveryIDOffset] < 27 {
+		return sig
+	}
+	normalized := common.CopyBytes(sig)
+	normalized[crypto.RecoveryIDOffset] -= 27
+	return normalized
+}
+
+// WithRemoteSignerHttpClient set custom http client for the remote signer
+func WithRemoteSignerHttpClient(client httpClient) func(signer *RemoteSigner) {
+	return func(signer *RemoteSigner) {
+		signer.client = client
+	}
+}
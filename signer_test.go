@@ -0,0 +1,168 @@
+package flashbotsrpc_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+// otherKeySigner claims to sign for address, but signs with another key
+type otherKeySigner struct {
+	flashbotsrpc.Signer
+	address common.Address
+}
+
+func (s otherKeySigner) Address() common.Address {
+	return s.address
+}
+
+// walletSigner returns V as 27 or 28, like wallets
+type walletSigner struct {
+	flashbotsrpc.Signer
+}
+
+func (s walletSigner) SignHashContext(ctx context.Context, hash []byte) ([]byte, error) {
+	sig, err := s.Signer.SignHashContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// blockingSigner doesn't sign before ctx is done
+type blockingSigner struct {
+	flashbotsrpc.Signer
+}
+
+func (s blockingSigner) SignHashContext(ctx context.Context, hash []byte) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestPrivateKeySigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := flashbotsrpc.NewPrivateKeySigner(key)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer.Address())
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_sendBundle","params":[]}`)
+	signature, err := flashbotsrpc.FlashbotsSignature(context.Background(), body, signer)
+	require.NoError(t, err)
+
+	parts := strings.Split(signature, ":")
+	require.Len(t, parts, 2)
+	require.Equal(t, signer.Address().Hex(), parts[0])
+
+	sig, err := hexutil.Decode(parts[1])
+	require.NoError(t, err)
+	pubKey, err := crypto.SigToPub(accounts.TextHash([]byte(crypto.Keccak256Hash(body).Hex())), sig)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), crypto.PubkeyToAddress(*pubKey))
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	key := &keystore.Key{Address: crypto.PubkeyToAddress(privKey.PublicKey), PrivateKey: privKey}
+	keyJSON, err := keystore.EncryptKey(key, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(path, keyJSON, 0o600))
+
+	signer, err := flashbotsrpc.NewKeystoreSigner(path, "secret")
+	require.NoError(t, err)
+	require.Equal(t, key.Address, signer.Address())
+
+	_, err = flashbotsrpc.NewKeystoreSigner(path, "wrong")
+	require.Error(t, err)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	localSigner := flashbotsrpc.NewPrivateKeySigner(key)
+
+	service := flashbotstest.NewSigningService(localSigner)
+	defer service.Close()
+
+	// The relay checks the signature was made by the remote key
+	relay := flashbotstest.NewNode()
+	defer relay.Close()
+	relay.SetResult("eth_sendBundle", flashbotsrpc.FlashbotsSendBundleResponse{BundleHash: "0x1234"})
+
+	signer := flashbotsrpc.NewRemoteSigner(service.URL, localSigner.Address())
+	rpc := flashbotsrpc.NewFlashbotsRPC(relay.URL)
+	res, err := rpc.FlashbotsSendBundleContext(context.Background(), signer, flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.NoError(t, err)
+	require.Equal(t, "0x1234", res.BundleHash)
+	require.Equal(t, localSigner.Address(), relay.Requests()[0].Signer)
+
+	// Unknown key
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = flashbotsrpc.NewRemoteSigner(service.URL, crypto.PubkeyToAddress(otherKey.PublicKey)).SignHash(make([]byte, 32))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown key")
+}
+
+func TestRemoteSignerAddressMismatch(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	// The service signs with a different key than requested
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	service := flashbotstest.NewSigningService(otherKeySigner{flashbotsrpc.NewPrivateKeySigner(otherKey), address})
+	defer service.Close()
+
+	_, err = flashbotsrpc.NewRemoteSigner(service.URL, address).SignHash(make([]byte, 32))
+	require.True(t, errors.Is(err, flashbotsrpc.ErrSignerAddressMismatch))
+}
+
+func TestRemoteSignerNormalizesV(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	// The service returns V as 27 or 28, like wallets
+	service := flashbotstest.NewSigningService(walletSigner{flashbotsrpc.NewPrivateKeySigner(key)})
+	defer service.Close()
+
+	sig, err := flashbotsrpc.NewRemoteSigner(service.URL, crypto.PubkeyToAddress(key.PublicKey)).SignHash(make([]byte, 32))
+	require.NoError(t, err)
+	require.Less(t, sig[crypto.RecoveryIDOffset], byte(2))
+}
+
+func TestRemoteSignerContext(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	// The service doesn't answer before the request is cancelled
+	service := flashbotstest.NewSigningService(blockingSigner{flashbotsrpc.NewPrivateKeySigner(key)})
+	defer service.Close()
+
+	relay := flashbotstest.NewNode()
+	defer relay.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	signer := flashbotsrpc.NewRemoteSigner(service.URL, crypto.PubkeyToAddress(key.PublicKey))
+	_, err = flashbotsrpc.NewFlashbotsRPC(relay.URL).FlashbotsSendBundleContext(ctx, signer, flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{"0x01"}, BlockNumber: "0x1"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Empty(t, relay.Requests())
+}
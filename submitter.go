@@ -0,0 +1,233 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BundleSender sends a bundle for the target block in its BlockNumber, e.g. to the relay or to several builders
+type BundleSender func(ctx context.Context, bundle FlashbotsSendBundleRequest) error
+
+// RelayBundleSender returns a BundleSender which sends bundles with FlashbotsSendBundle
+func RelayBundleSender(rpc *FlashbotsRPC, signer Signer) BundleSender {
+	return func(ctx context.Context, bundle FlashbotsSendBundleRequest) error {
+		_, err := rpc.FlashbotsSendBundleContext(ctx, signer, bundle)
+		return err
+	}
+}
+
+// BroadcastBundleSender returns a BundleSender which sends bundles with BroadcastBundle. Sending only fails if no
+// builder accepted the bundle.
+func BroadcastBundleSender(broadcaster *BuilderBroadcastRPC, signer Signer) BundleSender {
+	return func(ctx context.Context, bundle FlashbotsSendBundleRequest) error {
+		responses := broadcaster.BroadcastBundleContext(ctx, signer, bundle)
+		results := make([]BuilderBroadcastResult, len(responses))
+		for i, response := range responses {
+			results[i] = response.BuilderBroadcastResult
+		}
+		_, err := firstSuccess(results)
+		return err
+	}
+}
+
+// BundleSubmissionStatus is why a BundleSubmitter stopped submitting a bundle
+type BundleSubmissionStatus int
+
+const (
+	BundleSubmissionIncluded      BundleSubmissionStatus = iota // All transactions of the bundle were included
+	BundleSubmissionNonceConsumed                               // The nonce of a bundle transaction was used by another transaction
+	BundleSubmissionExpired                                     // The last block of the range passed without inclusion
+)
+
+func (s BundleSubmissionStatus) String() string {
+	switch s {
+	case BundleSubmissionIncluded:
+		return "included"
+	case BundleSubmissionNonceConsumed:
+		return "nonce consumed"
+	case BundleSubmissionExpired:
+		return "expired"
+	}
+	return fmt.Sprintf("BundleSubmissionStatus(%d)", int(s))
+}
+
+// BundleSubmissionResult is the outcome of BundleSubmitter.Submit
+type BundleSubmissionResult struct {
+	Status       BundleSubmissionStatus
+	BlockNumber  uint64           // Block which included the bundle, or the last block seen
+	Submitted    []uint64         // Target blocks the bundle was sent for
+	SubmitErrors map[uint64]error // Errors of failed submissions, by target block
+}
+
+// BundleSubmitter resubmits a bundle for every block of a range, until it's included, one of its nonces
+// is consumed, or the range expires. New blocks are detected by polling EthBlockNumber.
+type BundleSubmitter struct {
+	rpc          *FlashbotsRPC
+	send         BundleSender
+	log          logger
+	Debug        bool
+	PollInterval time.Duration
+}
+
+// NewBundleSubmitter creates a submitter which watches the chain with the given node client and sends bundles with send
+func NewBundleSubmitter(rpc *FlashbotsRPC, send BundleSender, options ...func(submitter *BundleSubmitter)) *BundleSubmitter {
+	submitter := &BundleSubmitter{
+		rpc:          rpc,
+		send:         send,
+		log:          log.New(os.Stderr, "", log.LstdFlags),
+		PollInterval: time.Second,
+	}
+	for _, option := range options {
+		option(submitter)
+	}
+	return submitter
+}
+
+// Submit sends the bundle for every block from fromBlock to toBlock (inclusive), each one after its parent block was seen.
+// The BlockNumber of the bundle is ignored. Submit blocks until the submission is finished or the context is done.
+//
+// Errors of the node are retried at the next poll, errors of single submissions are reported in the result.
+func (s *BundleSubmitter) Submit(ctx context.Context, bundle FlashbotsSendBundleRequest, fromBlock, toBlock uint64) (BundleSubmissionResult, error) {
+	result := BundleSubmissionResult{SubmitErrors: make(map[uint64]error)}
+
+	txs, err := decodeBundleTxs(bundle.Txs)
+	if err != nil {
+		return result, err
+	}
+
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	var lastBlock uint64
+	for {
+		head, err := s.rpc.EthBlockNumberContext(ctx)
+		if err != nil && ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		if err != nil {
+			s.debug("bundle submitter: failed to get block number: %v", err)
+		} else if uint64(head) > lastBlock {
+			lastBlock = uint64(head)
+			result.BlockNumber = lastBlock
+
+			status, blockNumber, done, err := s.check(ctx, txs)
+			if err != nil {
+				s.debug("bundle submitter: failed to check block %d: %v", lastBlock, err)
+			} else if done {
+				result.Status = status
+				if status == BundleSubmissionIncluded {
+					result.BlockNumber = blockNumber
+				}
+				return result, nil
+			}
+
+			if lastBlock >= toBlock {
+				result.Status = BundleSubmissionExpired
+				return result, nil
+			}
+
+			if lastBlock+1 >= fromBlock {
+				s.submit(ctx, bundle, lastBlock+1, &result)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// submit sends the bundle for the target block and records the outcome
+func (s *BundleSubmitter) submit(ctx context.Context, bundle FlashbotsSendBundleRequest, target uint64, result *BundleSubmissionResult) {
+	bundle.BlockNumber = hexutil.EncodeUint64(target)
+	if err := s.send(ctx, bundle); err != nil {
+		s.debug("bundle submitter: failed to submit for block %d: %v", target, err)
+		result.SubmitErrors[target] = err
+		return
+	}
+	result.Submitted = append(result.Submitted, target)
+}
+
+// check returns whether the bundle was included, and in which block, or one of its nonces was consumed
+func (s *BundleSubmitter) check(ctx context.Context, txs []bundleTx) (status BundleSubmissionStatus, blockNumber uint64, done bool, err error) {
+	included := 0
+	for _, tx := range txs {
+		receipt, err := s.rpc.EthGetTransactionReceiptContext(ctx, tx.hash.Hex())
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if receipt.TransactionHash != "" {
+			included++
+			blockNumber = uint64(receipt.BlockNumber)
+		}
+	}
+	if included == len(txs) {
+		return BundleSubmissionIncluded, blockNumber, true, nil
+	}
+
+	for _, tx := range txs {
+		nonce, err := s.rpc.EthGetTransactionCountContext(ctx, tx.from.Hex(), "latest")
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if uint64(nonce) > tx.nonce {
+			return BundleSubmissionNonceConsumed, 0, true, nil
+		}
+	}
+
+	return 0, 0, false, nil
+}
+
+func (s *BundleSubmitter) debug(format string, v ...interface{}) {
+	if s.Debug {
+		s.log.Println(fmt.Sprintf(format, v...))
+	}
+}
+
+// bundleTx is what's needed to track a signed bundle transaction on chain
+type bundleTx struct {
+	hash  common.Hash
+	from  common.Address
+	nonce uint64
+}
+
+// decodeBundleTxs decodes the raw signed transactions of a bundle
+func decodeBundleTxs(rawTxs []string) ([]bundleTx, error) {
+	txs := make([]bundleTx, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		data, err := hexutil.Decode(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+
+		txs[i] = bundleTx{hash: tx.Hash(), from: from, nonce: tx.Nonce()}
+	}
+	return txs, nil
+}
+
+// WithBundleSubmitterLogger set custom logger for the bundle submitter
+func WithBundleSubmitterLogger(l logger) func(submitter *BundleSubmitter) {
+	return func(submitter *BundleSubmitter) {
+		submitter.log = l
+	}
+}
@@ -0,0 +1,136 @@
+package flashbotsrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+// handleChain makes the node simulate a chain whose head advances by one block with every eth_blockNumber call,
+// starting at block 101. includedAt and nonceUsedAt are the heads from which the bundle is included or its nonce is
+// consumed by another transaction, 0 for never.
+func handleChain(t *testing.T, node *flashbotstest.Node, includedAt, nonceUsedAt int) {
+	head := 100
+	node.Handle("eth_blockNumber", func(params []json.RawMessage) (interface{}, error) {
+		head++
+		return hexutil.Uint64(head), nil
+	})
+	node.Handle("eth_getTransactionReceipt", func(params []json.RawMessage) (interface{}, error) {
+		if includedAt == 0 || head < includedAt {
+			return nil, nil
+		}
+		var hash common.Hash
+		require.NoError(t, json.Unmarshal(params[0], &hash))
+		return json.RawMessage(fmt.Sprintf(`{"transactionHash":"%s","blockNumber":"0x%x","status":"0x1"}`, hash.Hex(), includedAt)), nil
+	})
+	node.Handle("eth_getTransactionCount", func(params []json.RawMessage) (interface{}, error) {
+		if includedAt > 0 && head >= includedAt || nonceUsedAt > 0 && head >= nonceUsedAt {
+			return "0x1", nil
+		}
+		return "0x0", nil
+	})
+}
+
+func TestBundleSubmitter(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, _ := newSignedTx(t, key, 0)
+	bundle := flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{tx}}
+
+	tests := []struct {
+		name              string
+		includedAt        int
+		nonceUsedAt       int
+		fromBlock         uint64
+		toBlock           uint64
+		expectedStatus    flashbotsrpc.BundleSubmissionStatus
+		expectedBlock     uint64
+		expectedSubmitted []uint64
+	}{
+		{"included", 104, 0, 102, 110, flashbotsrpc.BundleSubmissionIncluded, 104, []uint64{102, 103, 104}},
+		{"nonce consumed", 0, 102, 102, 110, flashbotsrpc.BundleSubmissionNonceConsumed, 102, []uint64{102}},
+		{"expired", 0, 0, 102, 103, flashbotsrpc.BundleSubmissionExpired, 103, []uint64{102, 103}},
+		{"from block in the future", 0, 0, 104, 104, flashbotsrpc.BundleSubmissionExpired, 104, []uint64{104}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := flashbotstest.NewNode()
+			defer node.Close()
+			handleChain(t, node, test.includedAt, test.nonceUsedAt)
+
+			sent := []string{}
+			send := func(ctx context.Context, bundle flashbotsrpc.FlashbotsSendBundleRequest) error {
+				require.Equal(t, []string{tx}, bundle.Txs)
+				sent = append(sent, bundle.BlockNumber)
+				return nil
+			}
+
+			submitter := flashbotsrpc.NewBundleSubmitter(flashbotsrpc.NewFlashbotsRPC(node.URL), send)
+			submitter.PollInterval = time.Millisecond
+			result, err := submitter.Submit(context.Background(), bundle, test.fromBlock, test.toBlock)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedStatus, result.Status)
+			require.Equal(t, test.expectedBlock, result.BlockNumber)
+			require.Equal(t, test.expectedSubmitted, result.Submitted)
+			require.Empty(t, result.SubmitErrors)
+			require.Len(t, sent, len(test.expectedSubmitted))
+			for i, block := range test.expectedSubmitted {
+				require.Equal(t, hexutil.EncodeUint64(block), sent[i])
+			}
+		})
+	}
+}
+
+func TestBundleSubmitterSendErrors(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	handleChain(t, node, 0, 0)
+	builder := flashbotstest.NewNode()
+	defer builder.Close()
+	builder.SetFault("eth_sendBundle", flashbotstest.Fault{Message: "bundle rejected"})
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, _ := newSignedTx(t, key, 0)
+
+	send := flashbotsrpc.BroadcastBundleSender(flashbotsrpc.NewBuilderBroadcastRPC([]string{builder.URL}), flashbotsrpc.NewPrivateKeySigner(key))
+	submitter := flashbotsrpc.NewBundleSubmitter(flashbotsrpc.NewFlashbotsRPC(node.URL), send)
+	submitter.PollInterval = time.Millisecond
+	result, err := submitter.Submit(context.Background(), flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{tx}}, 102, 103)
+	require.NoError(t, err)
+	require.Equal(t, flashbotsrpc.BundleSubmissionExpired, result.Status)
+	require.Empty(t, result.Submitted)
+	require.Len(t, result.SubmitErrors, 2)
+	require.ErrorIs(t, result.SubmitErrors[102], flashbotsrpc.ErrAllBuildersFailed)
+	require.Contains(t, result.SubmitErrors[103].Error(), "bundle rejected")
+}
+
+func TestBundleSubmitterCancel(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	handleChain(t, node, 0, 0)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, _ := newSignedTx(t, key, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	send := func(ctx context.Context, bundle flashbotsrpc.FlashbotsSendBundleRequest) error {
+		cancel()
+		return nil
+	}
+	submitter := flashbotsrpc.NewBundleSubmitter(flashbotsrpc.NewFlashbotsRPC(node.URL), send)
+	submitter.PollInterval = time.Millisecond
+	_, err = submitter.Submit(ctx, flashbotsrpc.FlashbotsSendBundleRequest{Txs: []string{tx}}, 0, 1000)
+	require.ErrorIs(t, err, context.Canceled)
+}
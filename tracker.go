@@ -0,0 +1,147 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// ErrBlockNotFound means a block doesn't exist (yet)
+var ErrBlockNotFound = errors.New("block not found")
+
+// BundleInclusionStatus is whether and how a bundle landed on chain
+type BundleInclusionStatus int
+
+const (
+	BundleNotIncluded       BundleInclusionStatus = iota // No transaction was included, all nonces are still unused
+	BundleIncluded                                       // All transactions were included
+	BundlePartiallyIncluded                              // Some, but not all transactions were included
+	BundleFrontrun                                       // No transaction was included, but a nonce was used by another transaction
+)
+
+func (s BundleInclusionStatus) String() string {
+	switch s {
+	case BundleNotIncluded:
+		return "not included"
+	case BundleIncluded:
+		return "included"
+	case BundlePartiallyIncluded:
+		return "partially included"
+	case BundleFrontrun:
+		return "front-run"
+	}
+	return fmt.Sprintf("BundleInclusionStatus(%d)", int(s))
+}
+
+// BundleTxInclusion is whether and where a single transaction of a bundle was included
+type BundleTxInclusion struct {
+	Hash          common.Hash
+	Included      bool
+	BlockNumber   uint64      // Block which included the transaction
+	Position      int         // Index of the transaction in the block
+	Reverted      bool        // Whether the included transaction reverted
+	NonceConsumed bool        // Whether the nonce of the transaction, which wasn't included, was used by another transaction
+	ConsumedBy    common.Hash // Transaction which used the nonce, if it was found in the tracked blocks
+}
+
+// BundleInclusion is the result of InclusionTracker.Check
+type BundleInclusion struct {
+	Status BundleInclusionStatus
+	Txs    []BundleTxInclusion // In the order of the bundle
+}
+
+// InclusionTracker determines whether a bundle landed on chain, using the blocks and receipts of a node
+type InclusionTracker struct {
+	rpc *FlashbotsRPC
+}
+
+// NewInclusionTracker creates a tracker which inspects the chain with the given node client
+func NewInclusionTracker(rpc *FlashbotsRPC) *InclusionTracker {
+	return &InclusionTracker{rpc: rpc}
+}
+
+// Check looks for the signed transactions of a bundle in the target blocks, from fromBlock to toBlock (inclusive).
+// Transactions found outside the target blocks, e.g. because they were also sent to the public mempool, are
+// located with their receipt. All target blocks must exist, otherwise ErrBlockNotFound is returned.
+func (tracker *InclusionTracker) Check(ctx context.Context, rawTxs []string, fromBlock, toBlock uint64) (res BundleInclusion, err error) {
+	txs, err := decodeBundleTxs(rawTxs)
+	if err != nil {
+		return res, err
+	}
+
+	res.Txs = make([]BundleTxInclusion, len(txs))
+	for i, tx := range txs {
+		res.Txs[i].Hash = tx.hash
+	}
+
+	for number := fromBlock; number <= toBlock; number++ {
+		block, err := tracker.rpc.EthGetBlockByNumberContext(ctx, int(number), true)
+		if err != nil {
+			return res, err
+		}
+		if block == nil {
+			return res, fmt.Errorf("%w: %d", ErrBlockNotFound, number)
+		}
+
+		for position, blockTx := range block.Transactions {
+			hash := common.HexToHash(blockTx.Hash)
+			for i, tx := range txs {
+				if hash == tx.hash {
+					res.Txs[i].Included = true
+					res.Txs[i].BlockNumber = number
+					res.Txs[i].Position = position
+				} else if uint64(blockTx.Nonce) == tx.nonce && common.HexToAddress(blockTx.From) == tx.from {
+					res.Txs[i].NonceConsumed = true
+					res.Txs[i].ConsumedBy = hash
+				}
+			}
+		}
+	}
+
+	included := 0
+	for i, tx := range txs {
+		inclusion := &res.Txs[i]
+
+		// Receipts show whether transactions reverted, and find transactions included outside of the target blocks
+		receipt, err := tracker.rpc.EthGetTransactionReceiptContext(ctx, tx.hash.Hex())
+		if err != nil {
+			return res, err
+		}
+		if receipt.TransactionHash != "" {
+			inclusion.Included = true
+			inclusion.NonceConsumed = false
+			inclusion.ConsumedBy = common.Hash{}
+			inclusion.BlockNumber = uint64(receipt.BlockNumber)
+			inclusion.Position = receipt.TransactionIndex
+			inclusion.Reverted = receipt.Status == "0x0"
+			included++
+			continue
+		}
+
+		if !inclusion.NonceConsumed {
+			nonce, err := tracker.rpc.EthGetTransactionCountContext(ctx, tx.from.Hex(), "latest")
+			if err != nil {
+				return res, err
+			}
+			inclusion.NonceConsumed = uint64(nonce) > tx.nonce
+		}
+	}
+
+	switch {
+	case included == len(txs):
+		res.Status = BundleIncluded
+	case included > 0:
+		res.Status = BundlePartiallyIncluded
+	default:
+		res.Status = BundleNotIncluded
+		for _, inclusion := range res.Txs {
+			if inclusion.NonceConsumed {
+				res.Status = BundleFrontrun
+			}
+		}
+	}
+
+	return res, nil
+}
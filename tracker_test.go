@@ -0,0 +1,161 @@
+package flashbotsrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+type trackerNodeTx struct {
+	hash     common.Hash
+	from     common.Address
+	nonce    int
+	reverted bool
+}
+
+// handleBlocks makes the node answer with the given blocks, where every transaction has a receipt. nonces are the
+// transaction counts of the senders, 0 if not set.
+func handleBlocks(t *testing.T, node *flashbotstest.Node, blocks map[uint64][]trackerNodeTx, nonces map[common.Address]int) {
+	node.Handle("eth_getBlockByNumber", func(params []json.RawMessage) (interface{}, error) {
+		require.JSONEq(t, "true", string(params[1]))
+		var number hexutil.Uint64
+		require.NoError(t, json.Unmarshal(params[0], &number))
+		txs, ok := blocks[uint64(number)]
+		if !ok {
+			return nil, nil
+		}
+
+		blockTxs := []string{}
+		for _, tx := range txs {
+			blockTxs = append(blockTxs, fmt.Sprintf(`{"hash":"%s","from":"%s","nonce":"0x%x"}`, tx.hash.Hex(), strings.ToLower(tx.from.Hex()), tx.nonce))
+		}
+		return json.RawMessage(fmt.Sprintf(`{"number":"0x%x","transactions":[%s]}`, number, strings.Join(blockTxs, ","))), nil
+	})
+	node.Handle("eth_getTransactionReceipt", func(params []json.RawMessage) (interface{}, error) {
+		var hash common.Hash
+		require.NoError(t, json.Unmarshal(params[0], &hash))
+		for number, txs := range blocks {
+			for i, tx := range txs {
+				if tx.hash == hash {
+					status := "0x1"
+					if tx.reverted {
+						status = "0x0"
+					}
+					return json.RawMessage(fmt.Sprintf(`{"transactionHash":"%s","blockNumber":"0x%x","transactionIndex":"0x%x","status":"%s"}`, hash.Hex(), number, i, status)), nil
+				}
+			}
+		}
+		return nil, nil
+	})
+	node.Handle("eth_getTransactionCount", func(params []json.RawMessage) (interface{}, error) {
+		var address common.Address
+		require.NoError(t, json.Unmarshal(params[0], &address))
+		return hexutil.Uint64(nonces[address]), nil
+	})
+}
+
+func TestInclusionTracker(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	rawTx0, hash0 := newSignedTx(t, key, 0)
+	rawTx1, hash1 := newSignedTx(t, key, 1)
+	other := trackerNodeTx{hash: common.HexToHash("0x1234"), from: common.HexToAddress("0x0000000000000000000000000000000000000002")}
+	frontrunner := trackerNodeTx{hash: common.HexToHash("0x5678"), from: from, nonce: 0}
+	bundle := []string{rawTx0, rawTx1}
+
+	t.Run("included", func(t *testing.T) {
+		node := flashbotstest.NewNode()
+		defer node.Close()
+		handleBlocks(t, node, map[uint64][]trackerNodeTx{
+			100: {other},
+			101: {other, {hash: hash0, from: from, nonce: 0}, {hash: hash1, from: from, nonce: 1, reverted: true}},
+		}, map[common.Address]int{from: 2})
+
+		res, err := flashbotsrpc.NewInclusionTracker(flashbotsrpc.NewFlashbotsRPC(node.URL)).Check(context.Background(), bundle, 100, 101)
+		require.NoError(t, err)
+		require.Equal(t, flashbotsrpc.BundleIncluded, res.Status)
+		require.Equal(t, []flashbotsrpc.BundleTxInclusion{
+			{Hash: hash0, Included: true, BlockNumber: 101, Position: 1},
+			{Hash: hash1, Included: true, BlockNumber: 101, Position: 2, Reverted: true},
+		}, res.Txs)
+	})
+
+	t.Run("partially included", func(t *testing.T) {
+		node := flashbotstest.NewNode()
+		defer node.Close()
+		handleBlocks(t, node, map[uint64][]trackerNodeTx{
+			100: {{hash: hash0, from: from, nonce: 0}},
+		}, map[common.Address]int{from: 1})
+
+		res, err := flashbotsrpc.NewInclusionTracker(flashbotsrpc.NewFlashbotsRPC(node.URL)).Check(context.Background(), bundle, 100, 100)
+		require.NoError(t, err)
+		require.Equal(t, flashbotsrpc.BundlePartiallyIncluded, res.Status)
+		require.True(t, res.Txs[0].Included)
+		require.False(t, res.Txs[1].Included)
+		require.False(t, res.Txs[1].NonceConsumed)
+	})
+
+	t.Run("front-run", func(t *testing.T) {
+		node := flashbotstest.NewNode()
+		defer node.Close()
+		handleBlocks(t, node, map[uint64][]trackerNodeTx{
+			100: {frontrunner},
+		}, map[common.Address]int{from: 1})
+
+		res, err := flashbotsrpc.NewInclusionTracker(flashbotsrpc.NewFlashbotsRPC(node.URL)).Check(context.Background(), bundle, 100, 100)
+		require.NoError(t, err)
+		require.Equal(t, flashbotsrpc.BundleFrontrun, res.Status)
+		require.True(t, res.Txs[0].NonceConsumed)
+		require.Equal(t, frontrunner.hash, res.Txs[0].ConsumedBy)
+		require.False(t, res.Txs[1].NonceConsumed)
+	})
+
+	t.Run("included outside the target blocks", func(t *testing.T) {
+		node := flashbotstest.NewNode()
+		defer node.Close()
+		handleBlocks(t, node, map[uint64][]trackerNodeTx{
+			100: {other},
+			105: {{hash: hash0, from: from, nonce: 0}, {hash: hash1, from: from, nonce: 1}},
+		}, map[common.Address]int{from: 2})
+
+		res, err := flashbotsrpc.NewInclusionTracker(flashbotsrpc.NewFlashbotsRPC(node.URL)).Check(context.Background(), bundle, 100, 100)
+		require.NoError(t, err)
+		require.Equal(t, flashbotsrpc.BundleIncluded, res.Status)
+		require.Equal(t, uint64(105), res.Txs[1].BlockNumber)
+		require.Equal(t, 1, res.Txs[1].Position)
+	})
+
+	t.Run("not included", func(t *testing.T) {
+		node := flashbotstest.NewNode()
+		defer node.Close()
+		handleBlocks(t, node, map[uint64][]trackerNodeTx{
+			100: {other},
+		}, nil)
+
+		res, err := flashbotsrpc.NewInclusionTracker(flashbotsrpc.NewFlashbotsRPC(node.URL)).Check(context.Background(), bundle, 100, 100)
+		require.NoError(t, err)
+		require.Equal(t, flashbotsrpc.BundleNotIncluded, res.Status)
+		require.Equal(t, []flashbotsrpc.BundleTxInclusion{{Hash: hash0}, {Hash: hash1}}, res.Txs)
+	})
+
+	t.Run("block not found", func(t *testing.T) {
+		node := flashbotstest.NewNode()
+		defer node.Close()
+		handleBlocks(t, node, map[uint64][]trackerNodeTx{
+			100: {other},
+		}, nil)
+
+		_, err := flashbotsrpc.NewInclusionTracker(flashbotsrpc.NewFlashbotsRPC(node.URL)).Check(context.Background(), bundle, 100, 101)
+		require.ErrorIs(t, err, flashbotsrpc.ErrBlockNotFound)
+	})
+}
@@ -0,0 +1,200 @@
+package flashbotsrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// The typed API mirrors go-ethereum's ethclient: it takes and returns go-ethereum types, and returns
+// ethereum.NotFound if a block, transaction or receipt doesn't exist. A nil block number means the latest block.
+
+// ChainID returns the chain ID used for transaction replay protection.
+func (rpc *FlashbotsRPC) ChainID(ctx context.Context) (*big.Int, error) {
+	var result hexutil.Big
+	if err := rpc.callContext(ctx, "eth_chainId", &result); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// BlockNumber returns the number of the most recent block.
+func (rpc *FlashbotsRPC) BlockNumber(ctx context.Context) (uint64, error) {
+	var result hexutil.Uint64
+	err := rpc.callContext(ctx, "eth_blockNumber", &result)
+	return uint64(result), err
+}
+
+// BalanceAt returns the wei balance of the account at the given block.
+func (rpc *FlashbotsRPC) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var result hexutil.Big
+	if err := rpc.callContext(ctx, "eth_getBalance", &result, account, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// NonceAt returns the nonce of the account at the given block.
+func (rpc *FlashbotsRPC) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	var result hexutil.Uint64
+	err := rpc.callContext(ctx, "eth_getTransactionCount", &result, account, toBlockNumArg(blockNumber))
+	return uint64(result), err
+}
+
+// PendingNonceAt returns the nonce of the account in the pending state, i.e. the nonce for its next transaction.
+func (rpc *FlashbotsRPC) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var result hexutil.Uint64
+	err := rpc.callContext(ctx, "eth_getTransactionCount", &result, account, "pending")
+	return uint64(result), err
+}
+
+// CodeAt returns the contract code of the account at the given block.
+func (rpc *FlashbotsRPC) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	err := rpc.callContext(ctx, "eth_getCode", &result, account, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// StorageAt returns the value of the storage slot of the account at the given block.
+func (rpc *FlashbotsRPC) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	err := rpc.callContext(ctx, "eth_getStorageAt", &result, account, key, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// SuggestGasPrice returns the gas price suggested by the node.
+func (rpc *FlashbotsRPC) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var result hexutil.Big
+	if err := rpc.callContext(ctx, "eth_gasPrice", &result); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// SuggestGasTipCap returns the priority fee per gas suggested by the node.
+func (rpc *FlashbotsRPC) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var result hexutil.Big
+	if err := rpc.callContext(ctx, "eth_maxPriorityFeePerGas", &result); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// HeaderByNumber returns the header of the given block.
+func (rpc *FlashbotsRPC) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header := new(types.Header)
+	if err := rpc.callTyped(ctx, "eth_getBlockByNumber", header, toBlockNumArg(number), false); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// HeaderByHash returns the header of the block with the given hash.
+func (rpc *FlashbotsRPC) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header := new(types.Header)
+	if err := rpc.callTyped(ctx, "eth_getBlockByHash", header, hash, false); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// BlockByNumber returns the given block with its transactions and withdrawals. Uncles are not included.
+func (rpc *FlashbotsRPC) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return rpc.getTypedBlock(ctx, "eth_getBlockByNumber", toBlockNumArg(number), true)
+}
+
+// BlockByHash returns the block with the given hash, its transactions and withdrawals. Uncles are not included.
+func (rpc *FlashbotsRPC) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return rpc.getTypedBlock(ctx, "eth_getBlockByHash", hash, true)
+}
+
+func (rpc *FlashbotsRPC) getTypedBlock(ctx context.Context, method string, params ...interface{}) (*types.Block, error) {
+	var raw json.RawMessage
+	if err := rpc.callTyped(ctx, method, &raw, params...); err != nil {
+		return nil, err
+	}
+
+	header := new(types.Header)
+	if err := json.Unmarshal(raw, header); err != nil {
+		return nil, err
+	}
+
+	body := struct {
+		Transactions []*types.Transaction `json:"transactions"`
+		Withdrawals  []*types.Withdrawal  `json:"withdrawals"`
+	}{}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+
+	return types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: body.Transactions, Withdrawals: body.Withdrawals}), nil
+}
+
+// TransactionByHash returns the transaction with the given hash, and whether it's still pending.
+func (rpc *FlashbotsRPC) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	var raw json.RawMessage
+	if err := rpc.callTyped(ctx, "eth_getTransactionByHash", &raw, hash); err != nil {
+		return nil, false, err
+	}
+
+	tx = new(types.Transaction)
+	if err := json.Unmarshal(raw, tx); err != nil {
+		return nil, false, err
+	}
+
+	extra := struct {
+		BlockNumber *string `json:"blockNumber"`
+	}{}
+	if err := json.Unmarshal(raw, &extra); err != nil {
+		return nil, false, err
+	}
+
+	return tx, extra.BlockNumber == nil, nil
+}
+
+// TransactionReceipt returns the receipt of a mined transaction.
+func (rpc *FlashbotsRPC) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt := new(types.Receipt)
+	if err := rpc.callTyped(ctx, "eth_getTransactionReceipt", receipt, txHash); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// SendTransaction sends a signed transaction to the node.
+func (rpc *FlashbotsRPC) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return rpc.callContext(ctx, "eth_sendRawTransaction", nil, hexutil.Encode(data))
+}
+
+// callTyped is like callContext, but returns ethereum.NotFound if the result is null
+func (rpc *FlashbotsRPC) callTyped(ctx context.Context, method string, target interface{}, params ...interface{}) error {
+	result, err := rpc.CallContext(ctx, method, params...)
+	if err != nil {
+		return err
+	}
+	if len(result) == 0 || bytes.Equal(result, []byte("null")) {
+		return ethereum.NotFound
+	}
+	return json.Unmarshal(result, target)
+}
+
+// toBlockNumArg returns the block parameter for a block number. nil is the latest block, -1 the pending block.
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	if number.Cmp(big.NewInt(-1)) == 0 {
+		return "pending"
+	}
+	return hexutil.EncodeBig(number)
+}
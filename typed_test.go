@@ -0,0 +1,248 @@
+package flashbotsrpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/metachris/flashbotsrpc"
+	"github.com/metachris/flashbotsrpc/flashbotstest"
+	"github.com/stretchr/testify/require"
+)
+
+var zeroBloom = common.Bytes2Hex(make([]byte, 256))
+
+var typedTestHeader = `"parentHash":"0x0000000000000000000000000000000000000000000000000000000000000001",
+	"sha3Uncles":"0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+	"miner":"0x0000000000000000000000000000000000000002",
+	"stateRoot":"0x0000000000000000000000000000000000000000000000000000000000000003",
+	"transactionsRoot":"0x0000000000000000000000000000000000000000000000000000000000000004",
+	"receiptsRoot":"0x0000000000000000000000000000000000000000000000000000000000000005",
+	"logsBloom":"0x` + zeroBloom + `",
+	"difficulty":"0x0",
+	"number":"0x64",
+	"gasLimit":"0x1c9c380",
+	"gasUsed":"0x5208",
+	"timestamp":"0x6400",
+	"extraData":"0x",
+	"mixHash":"0x0000000000000000000000000000000000000000000000000000000000000000",
+	"nonce":"0x0000000000000000",
+	"baseFeePerGas":"0x7"`
+
+func newSignedTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64) (string, common.Hash) {
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	}), types.LatestSignerForChainID(big.NewInt(1)), key)
+	require.NoError(t, err)
+
+	data, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return hexutil.Encode(data), tx.Hash()
+}
+
+// rpcTransaction returns the JSON of a transaction as returned by eth_getTransactionByHash
+func rpcTransaction(t *testing.T, tx *types.Transaction, blockNumber *uint64) json.RawMessage {
+	data, err := tx.MarshalJSON()
+	require.NoError(t, err)
+
+	fields := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	fields["blockNumber"] = nil
+	if blockNumber != nil {
+		fields["blockNumber"] = hexutil.EncodeUint64(*blockNumber)
+	}
+
+	data, err = json.Marshal(fields)
+	require.NoError(t, err)
+	return data
+}
+
+func TestTypedState(t *testing.T) {
+	account := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_chainId", "0x1")
+	node.SetResult("eth_blockNumber", "0x64")
+	node.Handle("eth_getBalance", func(params []json.RawMessage) (interface{}, error) {
+		require.JSONEq(t, `"latest"`, string(params[1]))
+		return "0xde0b6b3a7640000", nil
+	})
+	node.Handle("eth_getTransactionCount", func(params []json.RawMessage) (interface{}, error) {
+		require.Contains(t, []string{`"0x63"`, `"pending"`}, string(params[1]))
+		return "0x5", nil
+	})
+	node.Handle("eth_getCode", func(params []json.RawMessage) (interface{}, error) {
+		require.JSONEq(t, `"pending"`, string(params[1]))
+		return "0x6001", nil
+	})
+	node.SetResult("eth_getStorageAt", "0x0000000000000000000000000000000000000000000000000000000000000002")
+	node.SetResult("eth_gasPrice", "0x3b9aca00")
+	node.SetResult("eth_maxPriorityFeePerGas", "0x1")
+
+	rpc := flashbotsrpc.NewFlashbotsRPC(node.URL)
+	ctx := context.Background()
+
+	chainID, err := rpc.ChainID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), chainID)
+
+	number, err := rpc.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), number)
+
+	balance, err := rpc.BalanceAt(ctx, account, nil)
+	require.NoError(t, err)
+	require.Equal(t, "1000000000000000000", balance.String())
+
+	nonce, err := rpc.NonceAt(ctx, account, big.NewInt(99))
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), nonce)
+
+	nonce, err = rpc.PendingNonceAt(ctx, account)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), nonce)
+
+	code, err := rpc.CodeAt(ctx, account, big.NewInt(-1))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x60, 0x01}, code)
+
+	value, err := rpc.StorageAt(ctx, account, common.Hash{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToHash("0x2").Bytes(), value)
+
+	gasPrice, err := rpc.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000000000), gasPrice)
+
+	tip, err := rpc.SuggestGasTipCap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), tip)
+}
+
+func TestTypedBlocksAndTransactions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	rawTx, hash := newSignedTx(t, key, 0)
+	tx := new(types.Transaction)
+	require.NoError(t, tx.UnmarshalBinary(hexutil.MustDecode(rawTx)))
+	minedAt := uint64(100)
+
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.Handle("eth_getBlockByNumber", func(params []json.RawMessage) (interface{}, error) {
+		require.JSONEq(t, `"0x64"`, string(params[0]))
+		return json.RawMessage(`{` + typedTestHeader + `,"transactions":[` + string(rpcTransaction(t, tx, &minedAt)) + `]}`), nil
+	})
+	node.SetResult("eth_getBlockByHash", nil)
+	node.SetResult("eth_getTransactionByHash", rpcTransaction(t, tx, nil))
+	node.SetResult("eth_getTransactionReceipt", json.RawMessage(`{"transactionHash":"`+hash.Hex()+`","blockNumber":"0x64","transactionIndex":"0x0",
+		"status":"0x1","cumulativeGasUsed":"0x5208","gasUsed":"0x5208","logsBloom":"0x`+zeroBloom+`","logs":[]}`))
+	node.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (interface{}, error) {
+		require.JSONEq(t, `"`+rawTx+`"`, string(params[0]))
+		return hash, nil
+	})
+
+	rpc := flashbotsrpc.NewFlashbotsRPC(node.URL)
+	ctx := context.Background()
+
+	header, err := rpc.HeaderByNumber(ctx, big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), header.Number)
+	require.Equal(t, big.NewInt(7), header.BaseFee)
+	require.Equal(t, common.HexToAddress("0x0000000000000000000000000000000000000002"), header.Coinbase)
+
+	block, err := rpc.BlockByNumber(ctx, big.NewInt(100))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), block.Number())
+	require.Len(t, block.Transactions(), 1)
+	require.Equal(t, hash, block.Transactions()[0].Hash())
+
+	_, err = rpc.HeaderByHash(ctx, common.Hash{})
+	require.ErrorIs(t, err, ethereum.NotFound)
+	_, err = rpc.BlockByHash(ctx, common.Hash{})
+	require.ErrorIs(t, err, ethereum.NotFound)
+
+	pendingTx, isPending, err := rpc.TransactionByHash(ctx, hash)
+	require.NoError(t, err)
+	require.True(t, isPending)
+	require.Equal(t, hash, pendingTx.Hash())
+
+	receipt, err := rpc.TransactionReceipt(ctx, hash)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	require.Equal(t, big.NewInt(100), receipt.BlockNumber)
+	require.Equal(t, uint64(21000), receipt.GasUsed)
+
+	require.NoError(t, rpc.SendTransaction(ctx, tx))
+}
+
+func TestTypedNotFound(t *testing.T) {
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	node.SetResult("eth_getTransactionByHash", nil)
+	node.SetResult("eth_getTransactionReceipt", nil)
+
+	rpc := flashbotsrpc.NewFlashbotsRPC(node.URL)
+	_, _, err := rpc.TransactionByHash(context.Background(), common.Hash{})
+	require.ErrorIs(t, err, ethereum.NotFound)
+	_, err = rpc.TransactionReceipt(context.Background(), common.Hash{})
+	require.ErrorIs(t, err, ethereum.NotFound)
+}
+
+func TestTypedShanghaiAndCancunBlocks(t *testing.T) {
+	withdrawals := `"withdrawalsRoot":"0x0000000000000000000000000000000000000000000000000000000000000008",
+		"withdrawals":[{"index":"0x1","validatorIndex":"0x2","address":"0x0000000000000000000000000000000000000009","amount":"0x3"}]`
+	blobTx := `{"type":"0x3","chainId":"0x1","nonce":"0x0","maxPriorityFeePerGas":"0x1","maxFeePerGas":"0x2",
+		"maxFeePerBlobGas":"0x1","gas":"0x5208","to":"0x0000000000000000000000000000000000000001","value":"0x0","input":"0x",
+		"accessList":[],"blobVersionedHashes":["0x0100000000000000000000000000000000000000000000000000000000000001"],
+		"v":"0x0","r":"0x1","s":"0x1","yParity":"0x0",
+		"hash":"0x0000000000000000000000000000000000000000000000000000000000000007"}`
+	node := flashbotstest.NewNode()
+	defer node.Close()
+	// Shanghai
+	node.SetResult("eth_getBlockByNumber", json.RawMessage(`{`+typedTestHeader+`,`+withdrawals+`,"transactions":[]}`))
+	// Cancun
+	node.SetResult("eth_getBlockByHash", json.RawMessage(`{`+typedTestHeader+`,`+withdrawals+`,"blobGasUsed":"0x20000","excessBlobGas":"0x0",
+		"parentBeaconBlockRoot":"0x000000000000000000000000000000000000000000000000000000000000000a","transactions":[`+blobTx+`]}`))
+
+	rpc := flashbotsrpc.NewFlashbotsRPC(node.URL)
+	ctx := context.Background()
+
+	header, err := rpc.HeaderByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToHash("0x8"), *header.WithdrawalsHash)
+	require.Nil(t, header.BlobGasUsed)
+
+	block, err := rpc.BlockByNumber(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, block.Withdrawals(), 1)
+	require.Equal(t, uint64(2), block.Withdrawals()[0].Validator)
+	require.Equal(t, uint64(3), block.Withdrawals()[0].Amount)
+
+	header, err = rpc.HeaderByHash(ctx, common.Hash{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x20000), *header.BlobGasUsed)
+	require.Equal(t, uint64(0), *header.ExcessBlobGas)
+	require.Equal(t, common.HexToHash("0xa"), *header.ParentBeaconRoot)
+
+	block, err = rpc.BlockByHash(ctx, common.Hash{})
+	require.NoError(t, err)
+	require.Len(t, block.Withdrawals(), 1)
+	require.Len(t, block.Transactions(), 1)
+	require.Equal(t, uint8(types.BlobTxType), block.Transactions()[0].Type())
+	require.Equal(t, []common.Hash{common.HexToHash("0x0100000000000000000000000000000000000000000000000000000000000001")}, block.Transactions()[0].BlobHashes())
+}
@@ -3,16 +3,24 @@ package flashbotsrpc
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"time"
-	"unsafe"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
 )
 
 // ErrRelayErrorResponse means it's a standard Flashbots relay error response - probably a user error rather than JSON or network error
 var ErrRelayErrorResponse = errors.New("relay error response")
 
+// ErrMissingBatchResponse is set on a BatchElem if the server didn't send a response for it
+var ErrMissingBatchResponse = errors.New("missing response in batch")
+
+// ErrInvalidBatchResponseID means the server answered a batch request with an unknown or duplicate ID
+var ErrInvalidBatchResponseID = errors.New("invalid response ID in batch")
+
 // Syncing - object with syncing data info
 type Syncing struct {
 	IsSyncing     bool
@@ -29,7 +37,7 @@ func (s *Syncing) UnmarshalJSON(data []byte) error {
 	}
 
 	proxy.IsSyncing = true
-	*s = *(*Syncing)(unsafe.Pointer(proxy))
+	*s = proxy.toSyncing()
 
 	return nil
 }
@@ -43,6 +51,15 @@ type T struct {
 	Value    *big.Int
 	Data     string
 	Nonce    int
+
+	// Typed transactions (EIP-2718). Type 0 is a legacy transaction and isn't sent.
+	Type                 int
+	ChainID              *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	AccessList           []AccessTuple // Sent if not nil
+	MaxFeePerBlobGas     *big.Int
+	BlobVersionedHashes  []string
 }
 
 // MarshalJSON implements the json.Unmarshaler interface.
@@ -68,6 +85,27 @@ func (t T) MarshalJSON() ([]byte, error) {
 	if t.Nonce > 0 {
 		params["nonce"] = IntToHex(t.Nonce)
 	}
+	if t.Type > 0 {
+		params["type"] = IntToHex(t.Type)
+	}
+	if t.ChainID != nil {
+		params["chainId"] = BigToHex(*t.ChainID)
+	}
+	if t.MaxFeePerGas != nil {
+		params["maxFeePerGas"] = BigToHex(*t.MaxFeePerGas)
+	}
+	if t.MaxPriorityFeePerGas != nil {
+		params["maxPriorityFeePerGas"] = BigToHex(*t.MaxPriorityFeePerGas)
+	}
+	if t.AccessList != nil {
+		params["accessList"] = t.AccessList
+	}
+	if t.MaxFeePerBlobGas != nil {
+		params["maxFeePerBlobGas"] = BigToHex(*t.MaxFeePerBlobGas)
+	}
+	if len(t.BlobVersionedHashes) > 0 {
+		params["blobVersionedHashes"] = t.BlobVersionedHashes
+	}
 
 	return json.Marshal(params)
 }
@@ -85,6 +123,24 @@ type Transaction struct {
 	Gas              int
 	GasPrice         big.Int
 	Input            string
+
+	Type                 int
+	ChainID              *big.Int
+	MaxFeePerGas         *big.Int // EIP-1559 and blob transactions
+	MaxPriorityFeePerGas *big.Int
+	AccessList           []AccessTuple // EIP-2930 and later transactions
+	MaxFeePerBlobGas     *big.Int      // EIP-4844 blob transactions
+	BlobVersionedHashes  []string
+	V                    big.Int
+	R                    big.Int
+	S                    big.Int
+	YParity              *int
+}
+
+// AccessTuple - address and storage keys of an access list (EIP-2930)
+type AccessTuple struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -94,7 +150,7 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*t = *(*Transaction)(unsafe.Pointer(proxy))
+	*t = proxy.toTransaction()
 
 	return nil
 }
@@ -119,7 +175,7 @@ func (log *Log) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*log = *(*Log)(unsafe.Pointer(proxy))
+	*log = proxy.toLog()
 
 	return nil
 }
@@ -145,6 +201,10 @@ type TransactionReceipt struct {
 	LogsBloom         string
 	Root              string
 	Status            string
+	Type              int
+	EffectiveGasPrice *big.Int
+	BlobGasUsed       *int     // EIP-4844 blob transactions
+	BlobGasPrice      *big.Int // EIP-4844 blob transactions
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
@@ -154,7 +214,7 @@ func (t *TransactionReceipt) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*t = *(*TransactionReceipt)(unsafe.Pointer(proxy))
+	*t = proxy.toTransactionReceipt()
 
 	return nil
 }
@@ -179,6 +239,21 @@ type Block struct {
 	Timestamp        int
 	Uncles           []string
 	Transactions     []Transaction
+
+	BaseFeePerGas         *big.Int // Since London (EIP-1559)
+	WithdrawalsRoot       string   // Since Shanghai (EIP-4895)
+	Withdrawals           []Withdrawal
+	BlobGasUsed           *int   // Since Cancun (EIP-4844)
+	ExcessBlobGas         *int   // Since Cancun (EIP-4844)
+	ParentBeaconBlockRoot string // Since Cancun (EIP-4788)
+}
+
+// Withdrawal - validator withdrawal object (EIP-4895)
+type Withdrawal struct {
+	Index          int
+	ValidatorIndex int
+	Address        string
+	Amount         int // In Gwei
 }
 
 type proxySyncing struct {
@@ -188,6 +263,15 @@ type proxySyncing struct {
 	HighestBlock  hexInt `json:"highestBlock"`
 }
 
+func (proxy *proxySyncing) toSyncing() Syncing {
+	return Syncing{
+		IsSyncing:     proxy.IsSyncing,
+		StartingBlock: int(proxy.StartingBlock),
+		CurrentBlock:  int(proxy.CurrentBlock),
+		HighestBlock:  int(proxy.HighestBlock),
+	}
+}
+
 type proxyTransaction struct {
 	Hash             string  `json:"hash"`
 	Nonce            hexInt  `json:"nonce"`
@@ -200,6 +284,46 @@ type proxyTransaction struct {
 	Gas              hexInt  `json:"gas"`
 	GasPrice         hexBig  `json:"gasPrice"`
 	Input            string  `json:"input"`
+
+	Type                 hexInt        `json:"type"`
+	ChainID              *hexBig       `json:"chainId"`
+	MaxFeePerGas         *hexBig       `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexBig       `json:"maxPriorityFeePerGas"`
+	AccessList           []AccessTuple `json:"accessList"`
+	MaxFeePerBlobGas     *hexBig       `json:"maxFeePerBlobGas"`
+	BlobVersionedHashes  []string      `json:"blobVersionedHashes"`
+	V                    hexBig        `json:"v"`
+	R                    hexBig        `json:"r"`
+	S                    hexBig        `json:"s"`
+	YParity              *hexInt       `json:"yParity"`
+}
+
+func (proxy *proxyTransaction) toTransaction() Transaction {
+	return Transaction{
+		Hash:             proxy.Hash,
+		Nonce:            int(proxy.Nonce),
+		BlockHash:        proxy.BlockHash,
+		BlockNumber:      proxy.BlockNumber.toInt(),
+		TransactionIndex: proxy.TransactionIndex.toInt(),
+		From:             proxy.From,
+		To:               proxy.To,
+		Value:            big.Int(proxy.Value),
+		Gas:              int(proxy.Gas),
+		GasPrice:         big.Int(proxy.GasPrice),
+		Input:            proxy.Input,
+
+		Type:                 int(proxy.Type),
+		ChainID:              proxy.ChainID.toBig(),
+		MaxFeePerGas:         proxy.MaxFeePerGas.toBig(),
+		MaxPriorityFeePerGas: proxy.MaxPriorityFeePerGas.toBig(),
+		AccessList:           proxy.AccessList,
+		MaxFeePerBlobGas:     proxy.MaxFeePerBlobGas.toBig(),
+		BlobVersionedHashes:  proxy.BlobVersionedHashes,
+		V:                    big.Int(proxy.V),
+		R:                    big.Int(proxy.R),
+		S:                    big.Int(proxy.S),
+		YParity:              proxy.YParity.toInt(),
+	}
 }
 
 type proxyLog struct {
@@ -214,18 +338,72 @@ type proxyLog struct {
 	Topics           []string `json:"topics"`
 }
 
+func (proxy *proxyLog) toLog() Log {
+	return Log{
+		Removed:          proxy.Removed,
+		LogIndex:         int(proxy.LogIndex),
+		TransactionIndex: int(proxy.TransactionIndex),
+		TransactionHash:  proxy.TransactionHash,
+		BlockNumber:      int(proxy.BlockNumber),
+		BlockHash:        proxy.BlockHash,
+		Address:          proxy.Address,
+		Data:             proxy.Data,
+		Topics:           proxy.Topics,
+	}
+}
+
 type proxyTransactionReceipt struct {
-	TransactionHash   string `json:"transactionHash"`
-	TransactionIndex  hexInt `json:"transactionIndex"`
-	BlockHash         string `json:"blockHash"`
-	BlockNumber       hexInt `json:"blockNumber"`
-	CumulativeGasUsed hexInt `json:"cumulativeGasUsed"`
-	GasUsed           hexInt `json:"gasUsed"`
-	ContractAddress   string `json:"contractAddress,omitempty"`
-	Logs              []Log  `json:"logs"`
-	LogsBloom         string `json:"logsBloom"`
-	Root              string `json:"root"`
-	Status            string `json:"status,omitempty"`
+	TransactionHash   string  `json:"transactionHash"`
+	TransactionIndex  hexInt  `json:"transactionIndex"`
+	BlockHash         string  `json:"blockHash"`
+	BlockNumber       hexInt  `json:"blockNumber"`
+	CumulativeGasUsed hexInt  `json:"cumulativeGasUsed"`
+	GasUsed           hexInt  `json:"gasUsed"`
+	ContractAddress   string  `json:"contractAddress,omitempty"`
+	Logs              []Log   `json:"logs"`
+	LogsBloom         string  `json:"logsBloom"`
+	Root              string  `json:"root"`
+	Status            string  `json:"status,omitempty"`
+	Type              hexInt  `json:"type"`
+	EffectiveGasPrice *hexBig `json:"effectiveGasPrice"`
+	BlobGasUsed       *hexInt `json:"blobGasUsed"`
+	BlobGasPrice      *hexBig `json:"blobGasPrice"`
+}
+
+func (proxy *proxyTransactionReceipt) toTransactionReceipt() TransactionReceipt {
+	return TransactionReceipt{
+		TransactionHash:   proxy.TransactionHash,
+		TransactionIndex:  int(proxy.TransactionIndex),
+		BlockHash:         proxy.BlockHash,
+		BlockNumber:       int(proxy.BlockNumber),
+		CumulativeGasUsed: int(proxy.CumulativeGasUsed),
+		GasUsed:           int(proxy.GasUsed),
+		ContractAddress:   proxy.ContractAddress,
+		Logs:              proxy.Logs,
+		LogsBloom:         proxy.LogsBloom,
+		Root:              proxy.Root,
+		Status:            proxy.Status,
+		Type:              int(proxy.Type),
+		EffectiveGasPrice: proxy.EffectiveGasPrice.toBig(),
+		BlobGasUsed:       proxy.BlobGasUsed.toInt(),
+		BlobGasPrice:      proxy.BlobGasPrice.toBig(),
+	}
+}
+
+type proxyWithdrawal struct {
+	Index          hexInt `json:"index"`
+	ValidatorIndex hexInt `json:"validatorIndex"`
+	Address        string `json:"address"`
+	Amount         hexInt `json:"amount"`
+}
+
+func (proxy *proxyWithdrawal) toWithdrawal() Withdrawal {
+	return Withdrawal{
+		Index:          int(proxy.Index),
+		ValidatorIndex: int(proxy.ValidatorIndex),
+		Address:        proxy.Address,
+		Amount:         int(proxy.Amount),
+	}
 }
 
 type hexInt int
@@ -237,6 +415,15 @@ func (i *hexInt) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// toInt returns nil for a nil value, for optional fields
+func (i *hexInt) toInt() *int {
+	if i == nil {
+		return nil
+	}
+	result := int(*i)
+	return &result
+}
+
 type hexBig big.Int
 
 func (i *hexBig) UnmarshalJSON(data []byte) error {
@@ -246,36 +433,21 @@ func (i *hexBig) UnmarshalJSON(data []byte) error {
 	return err
 }
 
-type proxyBlock interface {
-	toBlock() Block
-}
-
-type proxyBlockWithTransactions struct {
-	Number           hexInt             `json:"number"`
-	Hash             string             `json:"hash"`
-	ParentHash       string             `json:"parentHash"`
-	Nonce            string             `json:"nonce"`
-	Sha3Uncles       string             `json:"sha3Uncles"`
-	LogsBloom        string             `json:"logsBloom"`
-	TransactionsRoot string             `json:"transactionsRoot"`
-	StateRoot        string             `json:"stateRoot"`
-	Miner            string             `json:"miner"`
-	Difficulty       hexBig             `json:"difficulty"`
-	TotalDifficulty  hexBig             `json:"totalDifficulty"`
-	ExtraData        string             `json:"extraData"`
-	Size             hexInt             `json:"size"`
-	GasLimit         hexInt             `json:"gasLimit"`
-	GasUsed          hexInt             `json:"gasUsed"`
-	Timestamp        hexInt             `json:"timestamp"`
-	Uncles           []string           `json:"uncles"`
-	Transactions     []proxyTransaction `json:"transactions"`
+// toBig returns nil for a nil value, for optional fields
+func (i *hexBig) toBig() *big.Int {
+	if i == nil {
+		return nil
+	}
+	result := big.Int(*i)
+	return &result
 }
 
-func (proxy *proxyBlockWithTransactions) toBlock() Block {
-	return *(*Block)(unsafe.Pointer(proxy))
+type proxyBlock interface {
+	toBlock() Block
 }
 
-type proxyBlockWithoutTransactions struct {
+// proxyBlockHeader has the block fields except the transactions, which are either objects or hashes
+type proxyBlockHeader struct {
 	Number           hexInt   `json:"number"`
 	Hash             string   `json:"hash"`
 	ParentHash       string   `json:"parentHash"`
@@ -293,10 +465,17 @@ type proxyBlockWithoutTransactions struct {
 	GasUsed          hexInt   `json:"gasUsed"`
 	Timestamp        hexInt   `json:"timestamp"`
 	Uncles           []string `json:"uncles"`
-	Transactions     []string `json:"transactions"`
+
+	BaseFeePerGas         *hexBig           `json:"baseFeePerGas"`
+	WithdrawalsRoot       string            `json:"withdrawalsRoot"`
+	Withdrawals           []proxyWithdrawal `json:"withdrawals"`
+	BlobGasUsed           *hexInt           `json:"blobGasUsed"`
+	ExcessBlobGas         *hexInt           `json:"excessBlobGas"`
+	ParentBeaconBlockRoot string            `json:"parentBeaconBlockRoot"`
 }
 
-func (proxy *proxyBlockWithoutTransactions) toBlock() Block {
+// toBlock returns the block without transactions
+func (proxy *proxyBlockHeader) toBlock() Block {
 	block := Block{
 		Number:           int(proxy.Number),
 		Hash:             proxy.Hash,
@@ -315,8 +494,48 @@ func (proxy *proxyBlockWithoutTransactions) toBlock() Block {
 		GasUsed:          int(proxy.GasUsed),
 		Timestamp:        int(proxy.Timestamp),
 		Uncles:           proxy.Uncles,
+
+		BaseFeePerGas:         proxy.BaseFeePerGas.toBig(),
+		WithdrawalsRoot:       proxy.WithdrawalsRoot,
+		BlobGasUsed:           proxy.BlobGasUsed.toInt(),
+		ExcessBlobGas:         proxy.ExcessBlobGas.toInt(),
+		ParentBeaconBlockRoot: proxy.ParentBeaconBlockRoot,
+	}
+
+	if proxy.Withdrawals != nil {
+		block.Withdrawals = make([]Withdrawal, len(proxy.Withdrawals))
+		for i := range proxy.Withdrawals {
+			block.Withdrawals[i] = proxy.Withdrawals[i].toWithdrawal()
+		}
 	}
 
+	return block
+}
+
+type proxyBlockWithTransactions struct {
+	proxyBlockHeader
+	Transactions []proxyTransaction `json:"transactions"`
+}
+
+func (proxy *proxyBlockWithTransactions) toBlock() Block {
+	block := proxy.proxyBlockHeader.toBlock()
+
+	block.Transactions = make([]Transaction, len(proxy.Transactions))
+	for i := range proxy.Transactions {
+		block.Transactions[i] = proxy.Transactions[i].toTransaction()
+	}
+
+	return block
+}
+
+type proxyBlockWithoutTransactions struct {
+	proxyBlockHeader
+	Transactions []string `json:"transactions"`
+}
+
+func (proxy *proxyBlockWithoutTransactions) toBlock() Block {
+	block := proxy.proxyBlockHeader.toBlock()
+
 	block.Transactions = make([]Transaction, len(proxy.Transactions))
 	for i := range proxy.Transactions {
 		block.Transactions[i] = Transaction{
@@ -353,37 +572,123 @@ type FlashbotsCallBundleParam struct {
 }
 
 type FlashbotsCallBundleResult struct {
-	CoinbaseDiff      string `json:"coinbaseDiff"`      // "2717471092204423",
-	EthSentToCoinbase string `json:"ethSentToCoinbase"` // "0",
-	FromAddress       string `json:"fromAddress"`       // "0x37ff310ab11d1928BB70F37bC5E3cf62Df09a01c",
-	GasFees           string `json:"gasFees"`           // "2717471092204423",
-	GasPrice          string `json:"gasPrice"`          // "43000001459",
-	GasUsed           int64  `json:"gasUsed"`           // 63197,
-	ToAddress         string `json:"toAddress"`         // "0xdAC17F958D2ee523a2206206994597C13D831ec7",
-	TxHash            string `json:"txHash"`            // "0xe2df005210bdc204a34ff03211606e5d8036740c686e9fe4e266ae91cf4d12df",
-	Value             string `json:"value"`             // "0x"
-	Error             string `json:"error"`
-	Revert            string `json:"revert"`
+	CoinbaseDiff      BigIntString    `json:"coinbaseDiff"`      // "2717471092204423",
+	EthSentToCoinbase BigIntString    `json:"ethSentToCoinbase"` // "0",
+	FromAddress       common.Address  `json:"fromAddress"`       // "0x37ff310ab11d1928BB70F37bC5E3cf62Df09a01c",
+	GasFees           BigIntString    `json:"gasFees"`           // "2717471092204423",
+	GasPrice          BigIntString    `json:"gasPrice"`          // "43000001459",
+	GasUsed           int64           `json:"gasUsed"`           // 63197,
+	ToAddress         *common.Address `json:"toAddress"`         // "0xdAC17F958D2ee523a2206206994597C13D831ec7", nil for contract creations
+	TxHash            common.Hash     `json:"txHash"`            // "0xe2df005210bdc204a34ff03211606e5d8036740c686e9fe4e266ae91cf4d12df",
+	Value             hexutil.Bytes   `json:"value"`             // "0x"
+	Error             string          `json:"error"`
+	Revert            string          `json:"revert"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Contract creations have an empty toAddress.
+func (r *FlashbotsCallBundleResult) UnmarshalJSON(data []byte) error {
+	type result FlashbotsCallBundleResult
+	proxy := struct {
+		*result
+		ToAddress string `json:"toAddress"`
+	}{result: (*result)(r)}
+	if err := json.Unmarshal(data, &proxy); err != nil {
+		return err
+	}
+
+	r.ToAddress = nil
+	if proxy.ToAddress != "" && proxy.ToAddress != "0x" {
+		if !common.IsHexAddress(proxy.ToAddress) {
+			return fmt.Errorf("invalid toAddress: %s", proxy.ToAddress)
+		}
+		to := common.HexToAddress(proxy.ToAddress)
+		r.ToAddress = &to
+	}
+	return nil
+}
+
+// EffectivePriorityFee returns the priority fee per gas the transaction paid to the coinbase, without direct payments
+func (r FlashbotsCallBundleResult) EffectivePriorityFee() *big.Int {
+	if r.GasUsed == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Div(r.GasFees.Big(), big.NewInt(r.GasUsed))
 }
 
 type FlashbotsCallBundleResponse struct {
-	BundleGasPrice    string                      `json:"bundleGasPrice"`    // "43000001459",
+	BundleGasPrice    BigIntString                `json:"bundleGasPrice"`    // "43000001459",
 	BundleHash        string                      `json:"bundleHash"`        // "0x2ca9c4d2ba00d8144d8e396a4989374443cb20fb490d800f4f883ad4e1b32158",
-	CoinbaseDiff      string                      `json:"coinbaseDiff"`      // "2717471092204423",
-	EthSentToCoinbase string                      `json:"ethSentToCoinbase"` // "0",
-	GasFees           string                      `json:"gasFees"`           // "2717471092204423",
+	CoinbaseDiff      BigIntString                `json:"coinbaseDiff"`      // "2717471092204423",
+	EthSentToCoinbase BigIntString                `json:"ethSentToCoinbase"` // "0",
+	GasFees           BigIntString                `json:"gasFees"`           // "2717471092204423",
 	Results           []FlashbotsCallBundleResult `json:"results"`           // [],
 	StateBlockNumber  int64                       `json:"stateBlockNumber"`  // 12960319,
 	TotalGasUsed      int64                       `json:"totalGasUsed"`      // 63197
 }
 
+// Profit returns what the coinbase earns with the bundle in wei: gas fees and direct payments
+func (r FlashbotsCallBundleResponse) Profit() *big.Int {
+	return r.CoinbaseDiff.Big()
+}
+
+// BigIntString is a big integer which is encoded as a decimal string in JSON, e.g. "2717471092204423"
+type BigIntString struct {
+	Int *big.Int // nil if the string was empty
+	Raw string   // The original string
+}
+
+// NewBigIntString returns a BigIntString with the given value
+func NewBigIntString(i *big.Int) BigIntString {
+	return BigIntString{Int: i, Raw: i.String()}
+}
+
+// Big returns a copy of the value, or zero if it's empty
+func (b BigIntString) Big() *big.Int {
+	if b.Int == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(b.Int)
+}
+
+// String returns the original string
+func (b BigIntString) String() string {
+	return b.Raw
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b BigIntString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.Raw)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Numbers are accepted as well.
+func (b *BigIntString) UnmarshalJSON(data []byte) error {
+	raw := string(bytes.Trim(data, `"`))
+	if raw == "null" || raw == "" {
+		*b = BigIntString{}
+		return nil
+	}
+
+	i, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return fmt.Errorf("invalid decimal integer: %s", raw)
+	}
+	*b = BigIntString{Int: i, Raw: raw}
+	return nil
+}
+
 // sendBundle
 type FlashbotsSendBundleRequest struct {
-	Txs          []string  `json:"txs"`                         // Array[String], A list of signed transactions to execute in an atomic bundle
-	BlockNumber  string    `json:"blockNumber"`                 // String, a hex encoded block number for which this bundle is valid on
-	MinTimestamp *uint64   `json:"minTimestamp,omitempty"`      // (Optional) Number, the minimum timestamp for which this bundle is valid, in seconds since the unix epoch
-	MaxTimestamp *uint64   `json:"maxTimestamp,omitempty"`      // (Optional) Number, the maximum timestamp for which this bundle is valid, in seconds since the unix epoch
-	RevertingTxs *[]string `json:"revertingTxHashes,omitempty"` // (Optional) Array[String], A list of tx hashes that are allowed to revert
+	Txs             []string  `json:"txs"`                         // Array[String], A list of signed transactions to execute in an atomic bundle
+	BlockNumber     string    `json:"blockNumber"`                 // String, a hex encoded block number for which this bundle is valid on
+	MinTimestamp    *uint64   `json:"minTimestamp,omitempty"`      // (Optional) Number, the minimum timestamp for which this bundle is valid, in seconds since the unix epoch
+	MaxTimestamp    *uint64   `json:"maxTimestamp,omitempty"`      // (Optional) Number, the maximum timestamp for which this bundle is valid, in seconds since the unix epoch
+	RevertingTxs    *[]string `json:"revertingTxHashes,omitempty"` // (Optional) Array[String], A list of tx hashes that are allowed to revert
+	ReplacementUUID string    `json:"replacementUuid,omitempty"`   // (Optional) String, UUIDv4 which can be used to replace or cancel this bundle
+}
+
+// cancelBundle
+type FlashbotsCancelBundleRequest struct {
+	ReplacementUUID string `json:"replacementUuid"` // String, the UUID the bundles to cancel were sent with
 }
 
 type FlashbotsGetBundleStatsParam struct {
@@ -420,9 +725,47 @@ type FlashbotsSendBundleResponse struct {
 	BundleHash string `json:"bundleHash"`
 }
 
+// BuilderBroadcastResult is how a single builder answered a broadcast request
+type BuilderBroadcastResult struct {
+	URL        string        `json:"url"`
+	Name       string        `json:"name"`       // Name of the builder, or its URL if it has none
+	StatusCode int           `json:"statusCode"` // 0 if the builder didn't answer
+	Latency    time.Duration `json:"latency"`
+	Err        error         `json:"err"` // Network, HTTP, JSON-RPC or relay error
+}
+
 type BuilderBroadcastResponse struct {
+	BuilderBroadcastResult
 	BundleResponse FlashbotsSendBundleResponse `json:"bundleResponse"`
-	Err            error                       `json:"err"`
+}
+
+type BuilderBroadcastCancelBundleResponse struct {
+	BuilderBroadcastResult
+}
+
+type BuilderBroadcastCallBundleResponse struct {
+	BuilderBroadcastResult
+	CallBundleResponse FlashbotsCallBundleResponse `json:"callBundleResponse"`
+}
+
+type BuilderBroadcastMevSendBundleResponse struct {
+	BuilderBroadcastResult
+	BundleResponse MevSendBundleResponse `json:"bundleResponse"`
+}
+
+type BuilderBroadcastPrivateTransactionResponse struct {
+	BuilderBroadcastResult
+	TxHash string `json:"txHash"`
+}
+
+type BuilderBroadcastCancelPrivateTransactionResponse struct {
+	BuilderBroadcastResult
+	Cancelled bool `json:"cancelled"`
+}
+
+type BuilderBroadcastRawTransactionResponse struct {
+	BuilderBroadcastResult
+	TxHash string `json:"txHash"`
 }
 
 // sendPrivateTransaction
@@ -439,3 +782,94 @@ type FlashbotsPrivateTxPreferences struct {
 type FlashbotsCancelPrivateTransactionRequest struct {
 	TxHash string `json:"txHash"`
 }
+
+// MEV-Share privacy hints, see https://docs.flashbots.net/flashbots-mev-share/searchers/understanding-bundles#privacy
+const (
+	MevShareHintCalldata         = "calldata"
+	MevShareHintContractAddress  = "contract_address"
+	MevShareHintLogs             = "logs"
+	MevShareHintFunctionSelector = "function_selector"
+	MevShareHintHash             = "hash"
+	MevShareHintTxHash           = "tx_hash"
+)
+
+// mev_sendBundle
+type MevSendBundleRequest struct {
+	Version   string             `json:"version"`            // String, bundle format version, "v0.1"
+	Inclusion MevBundleInclusion `json:"inclusion"`          // Blocks in which the bundle is valid
+	Body      []MevBundleBody    `json:"body"`               // Transactions, transaction hashes and nested bundles of this bundle
+	Validity  *MevBundleValidity `json:"validity,omitempty"` // (Optional) Refund requirements of this bundle
+	Privacy   *MevBundlePrivacy  `json:"privacy,omitempty"`  // (Optional) Data shared about this bundle, and builders it's sent to
+}
+
+type MevBundleInclusion struct {
+	BlockNumber hexutil.Uint64 `json:"block"`              // First block in which the bundle is valid
+	MaxBlock    hexutil.Uint64 `json:"maxBlock,omitempty"` // (Optional) Last block in which the bundle is valid, defaults to BlockNumber
+}
+
+// MevBundleBody is one element of a bundle body. Exactly one of Tx, Hash and Bundle must be set.
+type MevBundleBody struct {
+	Tx        string                `json:"tx,omitempty"`        // Signed transaction
+	Hash      string                `json:"hash,omitempty"`      // Hash of a transaction from the MEV-Share event stream, e.g. to backrun it
+	Bundle    *MevSendBundleRequest `json:"bundle,omitempty"`    // Nested bundle
+	CanRevert bool                  `json:"canRevert,omitempty"` // (Optional) Whether the transaction is allowed to revert, only valid with Tx
+}
+
+type MevBundleValidity struct {
+	Refund       []MevBundleRefund       `json:"refund,omitempty"`       // (Optional) Minimum refund each body element must receive
+	RefundConfig []MevBundleRefundConfig `json:"refundConfig,omitempty"` // (Optional) How refunds paid to this bundle are split between addresses
+}
+
+type MevBundleRefund struct {
+	BodyIdx int `json:"bodyIdx"` // Index of the body element which should receive the refund
+	Percent int `json:"percent"` // Minimum percent of the bundle's MEV which should be refunded
+}
+
+type MevBundleRefundConfig struct {
+	Address common.Address `json:"address"` // Address which receives the refund
+	Percent int            `json:"percent"` // Percent of the refund going to this address
+}
+
+type MevBundlePrivacy struct {
+	Hints    []string `json:"hints,omitempty"`    // (Optional) Data shared on the event stream, see the MevShareHint constants
+	Builders []string `json:"builders,omitempty"` // (Optional) Names of the builders this bundle may be sent to
+}
+
+type MevSendBundleResponse struct {
+	BundleHash common.Hash `json:"bundleHash"`
+}
+
+// mev_simBundle
+type MevSimBundleOverrides struct {
+	ParentBlock string          `json:"parentBlock,omitempty"` // (Optional) Block number or hash used as state for the simulation, defaults to latest
+	BlockNumber *hexutil.Big    `json:"blockNumber,omitempty"` // (Optional) Number of the simulated block, defaults to parent block + 1
+	Coinbase    *common.Address `json:"coinbase,omitempty"`    // (Optional) Coinbase of the simulated block
+	Timestamp   *hexutil.Uint64 `json:"timestamp,omitempty"`   // (Optional) Timestamp of the simulated block
+	GasLimit    *hexutil.Uint64 `json:"gasLimit,omitempty"`    // (Optional) Gas limit of the simulated block
+	BaseFee     *hexutil.Big    `json:"baseFee,omitempty"`     // (Optional) Base fee of the simulated block
+	Timeout     int64           `json:"timeout,omitempty"`     // (Optional) Simulation timeout in seconds
+}
+
+type MevSimBundleResponse struct {
+	Success         bool               `json:"success"`
+	Error           string             `json:"error,omitempty"`
+	StateBlock      hexutil.Uint64     `json:"stateBlock"`
+	MevGasPrice     hexutil.Big        `json:"mevGasPrice"`
+	Profit          hexutil.Big        `json:"profit"`
+	RefundableValue hexutil.Big        `json:"refundableValue"`
+	GasUsed         hexutil.Uint64     `json:"gasUsed"`
+	BodyLogs        []MevSimBundleLogs `json:"logs,omitempty"`
+}
+
+// MevSimBundleLogs are the logs of one body element: TxLogs for a transaction, BundleLogs for a nested bundle
+type MevSimBundleLogs struct {
+	TxLogs     []MevShareLog      `json:"txLogs,omitempty"`
+	BundleLogs []MevSimBundleLogs `json:"bundleLogs,omitempty"`
+}
+
+// MevShareLog is a log as used by MEV-Share, without block and transaction information
+type MevShareLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
@@ -3,8 +3,11 @@ package flashbotsrpc
 import (
 	"encoding/json"
 	"math/big"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -89,6 +92,193 @@ func TestTransactionUnmarshal(t *testing.T) {
 	require.Equal(t, *big.NewInt(0), tx.Value)
 }
 
+func TestTypedTransactionUnmarshal(t *testing.T) {
+	data := []byte(`{
+        "blockHash": "0x3003694478c108eaec173afcb55eafbb754a0b204567329f623438727ffa90d8",
+        "blockNumber": "0x12a05f2",
+        "from": "0x201354729f8d0f8b64e9a0c353c672c6a66b3857",
+        "gas": "0x5208",
+        "gasPrice": "0x3b9aca07",
+        "maxFeePerGas": "0x77359400",
+        "maxPriorityFeePerGas": "0x7",
+        "maxFeePerBlobGas": "0x3",
+        "hash": "0xfc7dcd42eb0b7898af2f52f7c5af3bd03cdf71ab8b3ed5b3d3a3ff0d91343cbe",
+        "input": "0x",
+        "nonce": "0x1",
+        "to": "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf",
+        "transactionIndex": "0x0",
+        "value": "0x0",
+        "type": "0x3",
+        "accessList": [{
+            "address": "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf",
+            "storageKeys": ["0x0000000000000000000000000000000000000000000000000000000000000001"]
+        }],
+        "blobVersionedHashes": ["0x01b0a4cdd5f55589f5c5b4d46c76704bb6ce95c0a8c09f77f197a57808dded28"],
+        "chainId": "0x1",
+        "v": "0x1",
+        "r": "0x2a",
+        "s": "0x2b",
+        "yParity": "0x1"
+    }`)
+
+	tx := new(Transaction)
+	err := json.Unmarshal(data, tx)
+
+	require.Nil(t, err)
+	require.Equal(t, 3, tx.Type)
+	require.Equal(t, big.NewInt(1), tx.ChainID)
+	require.Equal(t, *big.NewInt(1000000007), tx.GasPrice)
+	require.Equal(t, big.NewInt(2000000000), tx.MaxFeePerGas)
+	require.Equal(t, big.NewInt(7), tx.MaxPriorityFeePerGas)
+	require.Equal(t, big.NewInt(3), tx.MaxFeePerBlobGas)
+	require.Equal(t, []AccessTuple{{
+		Address:     "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf",
+		StorageKeys: []string{"0x0000000000000000000000000000000000000000000000000000000000000001"},
+	}}, tx.AccessList)
+	require.Equal(t, []string{"0x01b0a4cdd5f55589f5c5b4d46c76704bb6ce95c0a8c09f77f197a57808dded28"}, tx.BlobVersionedHashes)
+	require.Equal(t, int64(1), tx.V.Int64())
+	require.Equal(t, int64(42), tx.R.Int64())
+	require.Equal(t, int64(43), tx.S.Int64())
+	require.Equal(t, 1, *tx.YParity)
+
+	// Legacy transactions have none of the typed fields
+	err = json.Unmarshal([]byte(`{"hash": "0x1", "gasPrice": "0x1", "v": "0x25"}`), tx)
+	require.Nil(t, err)
+	require.Equal(t, 0, tx.Type)
+	require.Nil(t, tx.ChainID)
+	require.Nil(t, tx.MaxFeePerGas)
+	require.Nil(t, tx.AccessList)
+	require.Nil(t, tx.YParity)
+	require.Equal(t, int64(37), tx.V.Int64())
+}
+
+func TestBlockUnmarshal(t *testing.T) {
+	data := []byte(`{
+        "number": "0x12a05f2",
+        "hash": "0x2bdda43f649c564642101fc990f569dd855e60f88bf83e931f509a92c62700f9",
+        "gasLimit": "0x1c9c380",
+        "gasUsed": "0x5208",
+        "baseFeePerGas": "0x7",
+        "withdrawalsRoot": "0x5f3d47a7e4dcd2e2d6c7e6a3ee5b0e0c1b7b8a9b1c6d2f8e3a4b5c6d7e8f9a0b",
+        "withdrawals": [{
+            "index": "0x1a",
+            "validatorIndex": "0x2b",
+            "address": "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf",
+            "amount": "0xe4e1c0"
+        }],
+        "blobGasUsed": "0x20000",
+        "excessBlobGas": "0x0",
+        "parentBeaconBlockRoot": "0x3003694478c108eaec173afcb55eafbb754a0b204567329f623438727ffa90d8",
+        "transactions": %s
+    }`)
+
+	withdrawals := []Withdrawal{{Index: 26, ValidatorIndex: 43, Address: "0xd10e3be2bc8f959bc8c41cf65f60de721cf89adf", Amount: 15000000}}
+	check := func(block Block) {
+		require.Equal(t, big.NewInt(7), block.BaseFeePerGas)
+		require.Equal(t, "0x5f3d47a7e4dcd2e2d6c7e6a3ee5b0e0c1b7b8a9b1c6d2f8e3a4b5c6d7e8f9a0b", block.WithdrawalsRoot)
+		require.Equal(t, withdrawals, block.Withdrawals)
+		require.Equal(t, 131072, *block.BlobGasUsed)
+		require.Equal(t, 0, *block.ExcessBlobGas)
+		require.Equal(t, "0x3003694478c108eaec173afcb55eafbb754a0b204567329f623438727ffa90d8", block.ParentBeaconBlockRoot)
+	}
+
+	withTxs := new(proxyBlockWithTransactions)
+	require.Nil(t, json.Unmarshal([]byte(strings.Replace(string(data), "%s", `[{"hash": "0x1", "type": "0x2"}]`, 1)), withTxs))
+	block := withTxs.toBlock()
+	check(block)
+	require.Equal(t, 2, block.Transactions[0].Type)
+
+	withoutTxs := new(proxyBlockWithoutTransactions)
+	require.Nil(t, json.Unmarshal([]byte(strings.Replace(string(data), "%s", `["0x1"]`, 1)), withoutTxs))
+	block = withoutTxs.toBlock()
+	check(block)
+	require.Equal(t, "0x1", block.Transactions[0].Hash)
+
+	// Blocks before London have none of the new fields
+	preLondon := new(proxyBlockWithTransactions)
+	require.Nil(t, json.Unmarshal([]byte(`{"number": "0x1", "transactions": []}`), preLondon))
+	block = preLondon.toBlock()
+	require.Nil(t, block.BaseFeePerGas)
+	require.Nil(t, block.Withdrawals)
+	require.Nil(t, block.BlobGasUsed)
+}
+
+// fieldNames returns the names of the fields of a struct type, including the fields of embedded structs
+func fieldNames(typ reflect.Type) []string {
+	names := []string{}
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Anonymous {
+			names = append(names, fieldNames(field.Type)...)
+		} else {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
+// fillNonZero sets every field of v, recursively, to a non-zero value
+func fillNonZero(t *testing.T, v reflect.Value) {
+	switch {
+	case v.Type() == reflect.TypeOf(hexBig{}):
+		v.Set(reflect.ValueOf(hexBig(*big.NewInt(1))))
+	case v.Kind() == reflect.Int:
+		v.SetInt(1)
+	case v.Kind() == reflect.String:
+		v.SetString("0x1")
+	case v.Kind() == reflect.Bool:
+		v.SetBool(true)
+	case v.Kind() == reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillNonZero(t, v.Elem())
+	case v.Kind() == reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillNonZero(t, v.Index(0))
+	case v.Kind() == reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fillNonZero(t, v.Field(i))
+		}
+	default:
+		t.Fatalf("can't fill %s", v.Type())
+	}
+}
+
+// The public types are decoded through proxy types, which must have the same fields, and every field must be copied
+func TestProxyTypesInSync(t *testing.T) {
+	syncing := new(proxySyncing)
+	tx := new(proxyTransaction)
+	log := new(proxyLog)
+	receipt := new(proxyTransactionReceipt)
+	withdrawal := new(proxyWithdrawal)
+	blockWithTxs := new(proxyBlockWithTransactions)
+	blockWithoutTxs := new(proxyBlockWithoutTransactions)
+
+	tests := []struct {
+		proxy   interface{}
+		convert func() interface{}
+	}{
+		{syncing, func() interface{} { return syncing.toSyncing() }},
+		{tx, func() interface{} { return tx.toTransaction() }},
+		{log, func() interface{} { return log.toLog() }},
+		{receipt, func() interface{} { return receipt.toTransactionReceipt() }},
+		{withdrawal, func() interface{} { return withdrawal.toWithdrawal() }},
+		{blockWithTxs, func() interface{} { return blockWithTxs.toBlock() }},
+		{blockWithoutTxs, func() interface{} { return blockWithoutTxs.toBlock() }},
+	}
+
+	for _, test := range tests {
+		proxy := reflect.ValueOf(test.proxy).Elem()
+		t.Run(proxy.Type().Name(), func(t *testing.T) {
+			fillNonZero(t, proxy)
+			public := reflect.ValueOf(test.convert())
+
+			require.ElementsMatch(t, fieldNames(public.Type()), fieldNames(proxy.Type()))
+			for i := 0; i < public.NumField(); i++ {
+				require.False(t, public.Field(i).IsZero(), "%s.%s is not copied", public.Type().Name(), public.Type().Field(i).Name)
+			}
+		})
+	}
+}
+
 func TestLogUnmarshal(t *testing.T) {
 	log := new(Log)
 	err := json.Unmarshal([]byte("111"), log)
@@ -172,3 +362,109 @@ func TestTransactionReceiptUnmarshal(t *testing.T) {
 	require.Equal(t, 6, receipt.Logs[0].LogIndex)
 	require.Equal(t, false, receipt.Logs[0].Removed)
 }
+
+func TestTypedTransactionReceiptUnmarshal(t *testing.T) {
+	data := []byte(`{
+        "blockHash": "0x3757b6efd7f82e3a832f0ec229b2fa36e622033ae7bad76b95763055a69374f7",
+        "blockNumber": "0x12a05f2",
+        "cumulativeGasUsed": "0x5208",
+        "gasUsed": "0x5208",
+        "logs": [],
+        "status": "0x1",
+        "transactionHash": "0xecd8a21609fa852c08249f6c767b7097481da34b9f8d2aae70067918955b4e69",
+        "transactionIndex": "0x0",
+        "type": "0x3",
+        "effectiveGasPrice": "0x3b9aca07",
+        "blobGasUsed": "0x20000",
+        "blobGasPrice": "0x1"
+    }`)
+
+	receipt := new(TransactionReceipt)
+	err := json.Unmarshal(data, receipt)
+
+	require.Nil(t, err)
+	require.Equal(t, 3, receipt.Type)
+	require.Equal(t, big.NewInt(1000000007), receipt.EffectiveGasPrice)
+	require.Equal(t, 131072, *receipt.BlobGasUsed)
+	require.Equal(t, big.NewInt(1), receipt.BlobGasPrice)
+}
+
+func TestFlashbotsCallBundleResponseUnmarshal(t *testing.T) {
+	data := []byte(`{
+		"bundleGasPrice": "43000001459",
+		"bundleHash": "0x2ca9c4d2ba00d8144d8e396a4989374443cb20fb490d800f4f883ad4e1b32158",
+		"coinbaseDiff": "2717471092204423",
+		"ethSentToCoinbase": "0",
+		"gasFees": "2717471092204423",
+		"results": [
+			{
+				"coinbaseDiff": "2717471092204423",
+				"ethSentToCoinbase": "0",
+				"fromAddress": "0x37ff310ab11d1928BB70F37bC5E3cf62Df09a01c",
+				"gasFees": "2717471092204423",
+				"gasPrice": "43000001459",
+				"gasUsed": 63197,
+				"toAddress": "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+				"txHash": "0xe2df005210bdc204a34ff03211606e5d8036740c686e9fe4e266ae91cf4d12df",
+				"value": "0x"
+			},
+			{
+				"coinbaseDiff": "1000000000000000000",
+				"ethSentToCoinbase": "1000000000000000000",
+				"fromAddress": "0x37ff310ab11d1928BB70F37bC5E3cf62Df09a01c",
+				"gasFees": "0",
+				"gasPrice": "0",
+				"gasUsed": 0,
+				"toAddress": "0x",
+				"txHash": "0xe2df005210bdc204a34ff03211606e5d8036740c686e9fe4e266ae91cf4d12e0",
+				"value": "0x0001"
+			}
+		],
+		"stateBlockNumber": 12960319,
+		"totalGasUsed": 63197
+	}`)
+
+	res := FlashbotsCallBundleResponse{}
+	err := json.Unmarshal(data, &res)
+	require.NoError(t, err)
+
+	require.Equal(t, "43000001459", res.BundleGasPrice.String())
+	require.Equal(t, big.NewInt(43000001459), res.BundleGasPrice.Int)
+	require.Equal(t, big.NewInt(2717471092204423), res.Profit())
+	require.Equal(t, int64(0), res.EthSentToCoinbase.Int.Int64())
+	require.Len(t, res.Results, 2)
+
+	result := res.Results[0]
+	require.Equal(t, common.HexToAddress("0x37ff310ab11d1928BB70F37bC5E3cf62Df09a01c"), result.FromAddress)
+	require.Equal(t, common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"), *result.ToAddress)
+	require.Equal(t, "0xe2df005210bdc204a34ff03211606e5d8036740c686e9fe4e266ae91cf4d12df", result.TxHash.Hex())
+	require.Empty(t, result.Value)
+	require.Equal(t, int64(63197), result.GasUsed)
+	require.Equal(t, big.NewInt(2717471092204423/63197), result.EffectivePriorityFee())
+
+	result = res.Results[1]
+	require.Nil(t, result.ToAddress)
+	require.Equal(t, []byte{0, 1}, []byte(result.Value))
+	require.Equal(t, big.NewInt(0), result.EffectivePriorityFee())
+
+	// The original strings are kept when encoding again
+	encoded, err := json.Marshal(res)
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"coinbaseDiff":"2717471092204423"`)
+}
+
+func TestBigIntStringUnmarshal(t *testing.T) {
+	var b BigIntString
+	require.NoError(t, json.Unmarshal([]byte(`"123456789012345678901234567890"`), &b))
+	require.Equal(t, "123456789012345678901234567890", b.Int.String())
+	require.Equal(t, "123456789012345678901234567890", b.String())
+
+	require.NoError(t, json.Unmarshal([]byte(`42`), &b))
+	require.Equal(t, big.NewInt(42), b.Int)
+
+	require.NoError(t, json.Unmarshal([]byte(`""`), &b))
+	require.Nil(t, b.Int)
+	require.Equal(t, big.NewInt(0), b.Big())
+
+	require.Error(t, json.Unmarshal([]byte(`"0x10"`), &b))
+}
@@ -0,0 +1,146 @@
+package flashbotsrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrMissingSignature means a request has no X-Flashbots-Signature header
+	ErrMissingSignature = errors.New("missing X-Flashbots-Signature header")
+
+	// ErrInvalidSignature means an X-Flashbots-Signature header is malformed or the signature can't be recovered
+	ErrInvalidSignature = errors.New("invalid X-Flashbots-Signature header")
+
+	// ErrSignerNotAllowed means a request is signed correctly, but not by an allowed address
+	ErrSignerNotAllowed = errors.New("signer is not allowed")
+)
+
+// DefaultMaxSignedBodySize is the default for SignatureMiddleware.MaxBodySize
+const DefaultMaxSignedBodySize = 10 * 1024 * 1024
+
+// VerifyFlashbotsSignature verifies the X-Flashbots-Signature header of a request body, as created by
+// CallWithFlashbotsSignature, and returns the address of the signer. It returns ErrSignerAddressMismatch if the
+// signature doesn't recover to the address in the header, e.g. because the body was modified.
+func VerifyFlashbotsSignature(header string, body []byte) (common.Address, error) {
+	if header == "" {
+		return common.Address{}, ErrMissingSignature
+	}
+
+	parts := strings.SplitN(header, ":", 2)
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		return common.Address{}, ErrInvalidSignature
+	}
+	address := common.HexToAddress(parts[0])
+
+	sig, err := hexutil.Decode(parts[1])
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+	sig = normalizeSignature(sig)
+
+	hashedBody := crypto.Keccak256Hash(body).Hex()
+	pubkey, err := crypto.SigToPub(accounts.TextHash([]byte(hashedBody)), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	if crypto.PubkeyToAddress(*pubkey) != address {
+		return common.Address{}, ErrSignerAddressMismatch
+	}
+	return address, nil
+}
+
+type signerContextKey struct{}
+
+// SignerFromContext returns the signer of a request verified by SignatureMiddleware
+func SignerFromContext(ctx context.Context) (common.Address, bool) {
+	address, ok := ctx.Value(signerContextKey{}).(common.Address)
+	return address, ok
+}
+
+// SignatureMiddleware is an http.Handler which verifies the X-Flashbots-Signature header of requests before passing
+// them to the next handler, with the signer in the request context (see SignerFromContext). Requests with a missing
+// or bad signature, or from a signer which isn't allowed, are rejected with HTTP 403 and a relay error response.
+type SignatureMiddleware struct {
+	next        http.Handler
+	allowed     map[common.Address]bool // All signers are allowed if empty
+	log         logger
+	Debug       bool
+	MaxBodySize int64 // Larger requests are rejected with HTTP 413
+}
+
+// NewSignatureMiddleware creates a middleware which passes requests with a valid signature to next
+func NewSignatureMiddleware(next http.Handler, options ...func(m *SignatureMiddleware)) *SignatureMiddleware {
+	m := &SignatureMiddleware{
+		next:        next,
+		allowed:     make(map[common.Address]bool),
+		log:         log.New(os.Stderr, "", log.LstdFlags),
+		MaxBodySize: DefaultMaxSignedBodySize,
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+func (m *SignatureMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, m.MaxBodySize))
+	if err != nil {
+		m.reject(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	signer, err := VerifyFlashbotsSignature(r.Header.Get("X-Flashbots-Signature"), body)
+	if err != nil {
+		m.reject(w, http.StatusForbidden, err)
+		return
+	}
+	if len(m.allowed) > 0 && !m.allowed[signer] {
+		m.reject(w, http.StatusForbidden, fmt.Errorf("%w: %s", ErrSignerNotAllowed, signer.Hex()))
+		return
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), signerContextKey{}, signer))
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	m.next.ServeHTTP(w, r)
+}
+
+func (m *SignatureMiddleware) reject(w http.ResponseWriter, statusCode int, err error) {
+	if m.Debug {
+		m.log.Println(fmt.Sprintf("signature middleware: rejected request: %v", err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(RelayErrorResponse{Error: err.Error()})
+}
+
+// WithAllowedSigners only allows requests signed by one of the addresses
+func WithAllowedSigners(addresses ...common.Address) func(m *SignatureMiddleware) {
+	return func(m *SignatureMiddleware) {
+		for _, address := range addresses {
+			m.allowed[address] = true
+		}
+	}
+}
+
+// WithSignatureMiddlewareLogger set custom logger for the middleware
+func WithSignatureMiddlewareLogger(l logger) func(m *SignatureMiddleware) {
+	return func(m *SignatureMiddleware) {
+		m.log = l
+	}
+}
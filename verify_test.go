@@ -0,0 +1,109 @@
+package flashbotsrpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestVerifyFlashbotsSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := NewPrivateKeySigner(key)
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_sendBundle","params":[]}`)
+	signature, err := flashbotsSignature(context.Background(), body, signer)
+	require.NoError(t, err)
+
+	address, err := VerifyFlashbotsSignature(signature, body)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), address)
+
+	// V of 27 or 28, as created by wallets
+	parts := strings.Split(signature, ":")
+	sig := hexutil.MustDecode(parts[1])
+	sig[64] += 27
+	address, err = VerifyFlashbotsSignature(parts[0]+":"+hexutil.Encode(sig), body)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), address)
+
+	_, err = VerifyFlashbotsSignature(signature, []byte(`{"jsonrpc":"2.0","id":2,"method":"eth_sendBundle","params":[]}`))
+	require.ErrorIs(t, err, ErrSignerAddressMismatch)
+
+	_, err = VerifyFlashbotsSignature(common.Address{}.Hex()+":"+parts[1], body)
+	require.ErrorIs(t, err, ErrSignerAddressMismatch)
+
+	_, err = VerifyFlashbotsSignature("", body)
+	require.ErrorIs(t, err, ErrMissingSignature)
+
+	for _, header := range []string{parts[1], "0x1234:" + parts[1], parts[0] + ":0x1234", parts[0] + ":nothex"} {
+		_, err = VerifyFlashbotsSignature(header, body)
+		require.ErrorIs(t, err, ErrInvalidSignature, header)
+	}
+}
+
+func TestSignatureMiddleware(t *testing.T) {
+	allowedKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	allowed := crypto.PubkeyToAddress(allowedKey.PublicKey)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signer, ok := SignerFromContext(r.Context())
+		require.True(t, ok)
+		require.Equal(t, allowed, signer)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "flashbots_getUserStats", gjson.GetBytes(body, "method").String())
+
+		_, err = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"is_high_priority":true}}`))
+		require.NoError(t, err)
+	})
+
+	server := httptest.NewServer(NewSignatureMiddleware(handler, WithAllowedSigners(allowed)))
+	defer server.Close()
+	rpc := NewFlashbotsRPC(server.URL)
+
+	stats, err := rpc.FlashbotsGetUserStats(allowedKey, 100)
+	require.NoError(t, err)
+	require.True(t, stats.IsHighPriority)
+
+	_, err = rpc.FlashbotsGetUserStats(otherKey, 100)
+	require.ErrorIs(t, err, ErrRelayErrorResponse)
+	require.Contains(t, err.Error(), ErrSignerNotAllowed.Error())
+
+	res, err := http.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusForbidden, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, ErrMissingSignature.Error(), gjson.GetBytes(body, "error").String())
+}
+
+func TestSignatureMiddlewareMaxBodySize(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should be rejected")
+	})
+	middleware := NewSignatureMiddleware(handler)
+	middleware.MaxBodySize = 10
+	server := httptest.NewServer(middleware)
+	defer server.Close()
+
+	_, err = NewFlashbotsRPC(server.URL).FlashbotsGetUserStats(key, 100)
+	require.ErrorIs(t, err, ErrRelayErrorResponse)
+}